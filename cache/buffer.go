@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// readBatchSize is the number of accesses a stripe holds before it asks for
+// the buffer to be drained into the policy.
+const readBatchSize = 64
+
+// readEntry is a recorded access, stamped with its place in the access
+// stream.
+type readEntry[K comparable] struct {
+	key K
+	seq uint64
+}
+
+// readStripe is one lossy ring of recorded accesses.
+type readStripe[K comparable] struct {
+	mu      sync.Mutex
+	entries []readEntry[K]
+
+	// Keep neighbouring stripes off the same cache line.
+	_ [64]byte
+}
+
+// readBuffer collects cache hits so that they can be passed to the eviction
+// policy in batches, instead of taking the policy's lock on every Get.
+// Each access goes to a stripe picked at random, not by key: readers of the
+// same hot key must not all queue on one stripe. Every access is stamped with
+// a sequence number, and drain replays them in that order, so the policy
+// sees each goroutine's accesses in the order they happened. A stripe that
+// is busy or full drops the access rather than block the reader, so under
+// contention the policy sees an approximation of the access stream, as in
+// Caffeine and Ristretto.
+type readBuffer[K comparable] struct {
+	stripes []readStripe[K]
+	mask    uint64
+	seq     atomic.Uint64 // Stamp of the latest recorded access
+	drained uint64        // Stamp of the latest access drained
+	scratch []K
+}
+
+// newReadBuffer creates a readBuffer with a few stripes per processor.
+func newReadBuffer[K comparable]() *readBuffer[K] {
+	n := 1
+	for n < 4*runtime.GOMAXPROCS(0) {
+		n <<= 1
+	}
+	return &readBuffer[K]{
+		stripes: make([]readStripe[K], n),
+		mask:    uint64(n - 1),
+	}
+}
+
+// record notes an access to key. It reports whether the stripe it landed in
+// is full and the buffer should be drained.
+func (b *readBuffer[K]) record(key K) bool {
+	// The runtime's per-thread generator is cheap and needs no lock.
+	stripe := &b.stripes[rand.Uint64()&b.mask]
+	if !stripe.mu.TryLock() {
+		// Someone else is recording into or draining this stripe; drop it.
+		return false
+	}
+	defer stripe.mu.Unlock()
+
+	if len(stripe.entries) >= readBatchSize {
+		return true
+	}
+	if stripe.entries == nil {
+		stripe.entries = make([]readEntry[K], 0, readBatchSize)
+	}
+	// Stamped under the stripe lock, so each stripe is in stamp order.
+	stripe.entries = append(stripe.entries, readEntry[K]{key, b.seq.Add(1)})
+	return len(stripe.entries) >= readBatchSize
+}
+
+// drain passes every recorded access to apply, in the order they were
+// recorded, and empties the buffer. Only one drain may run at a time.
+func (b *readBuffer[K]) drain(apply func(key K)) {
+	seq := b.seq.Load()
+	if seq == b.drained {
+		// Nothing was recorded since the last drain.
+		return
+	}
+
+	// Hold every stripe at once, so that no access can be recorded into a
+	// stripe already drained while a later one is still being read.
+	for i := range b.stripes {
+		b.stripes[i].mu.Lock()
+	}
+	// Stamps are only taken by accesses that are kept, so the stripes hold
+	// exactly the stamps after the last drain, and each access can be put
+	// straight into its place.
+	seq = b.seq.Load()
+	keys := slices.Grow(b.scratch[:0], int(seq-b.drained))[:seq-b.drained]
+	for i := range b.stripes {
+		stripe := &b.stripes[i]
+		for _, entry := range stripe.entries {
+			keys[entry.seq-b.drained-1] = entry.key
+		}
+		clear(stripe.entries)
+		stripe.entries = stripe.entries[:0]
+	}
+	b.drained = seq
+	for i := range b.stripes {
+		b.stripes[i].mu.Unlock()
+	}
+
+	for _, key := range keys {
+		apply(key)
+	}
+	clear(keys)
+	b.scratch = keys[:0]
+}
@@ -1,95 +1,500 @@
 package cache
 
 import (
-	"log"
+	"log/slog"
 	"sync"
+
+	"github.com/Varun0157/in-mem-cache/clock"
+	"github.com/Varun0157/in-mem-cache/internal/lru"
 )
 
 // Cache is a thread-safe, generic, in-memory cache.
 type Cache[K comparable, V any] struct {
 	capacity int
 	policy   EvictionPolicy[K]
+	weigher  func(key K, value V) int // nil means every entry weighs 1
+	clock    clock.Clock
+	stats    *StatsCounter // nil when stats are disabled
+	logger   *slog.Logger  // nil when logging is off
+	tracer   Tracer        // nil when tracing is off
+	name     string
+
+	mu        sync.RWMutex
+	storage   map[K]V
+	weights   map[K]int // Per-entry weights; only kept with a weigher
+	weight    int       // Total weight of the entries in storage
+	listeners []RemovalListener[K, V]
+
+	closed     bool
+	closeHooks []func()
+
+	// Hits are recorded here and handed to the policy in batches.
+	reads *readBuffer[K]
+}
+
+// New creates a new Cache with a given capacity and eviction policy. A nil
+// policy means LRU. Further options may be given as for NewWithOptions, but
+// capacity and a non-nil policy take precedence over theirs. Unlike
+// NewWithOptions, New does not fail: a capacity of 0 or less is replaced
+// with 1, with a warning if there is a logger.
+func New[K comparable, V any](capacity int, policy EvictionPolicy[K], opts ...Option[K, V]) *Cache[K, V] {
+	cfg := newConfig(opts)
+	cfg.capacity = capacity
+	if policy != nil {
+		cfg.policy = policy
+	}
+	if cfg.capacity <= 0 {
+		cfg.capacity = 1
+	}
+	if cfg.initialSize < 0 {
+		// Only a hint, so fall back to the default rather than fail
+		cfg.initialSizeSet = false
+	}
 
-	mu      sync.RWMutex
-	storage map[K]V
+	c := newCache(cfg)
+	if capacity <= 0 && c.logger != nil {
+		c.logger.Warn("cache capacity must be greater than 0, defaulting to 1", "capacity", capacity)
+	}
+	return c
 }
 
-// New creates a new Cache with a given capacity and eviction policy.
-func New[K comparable, V any](capacity int, policy EvictionPolicy[K]) *Cache[K, V] {
-	if capacity <= 0 {
-		log.Println("Cache capacity must be greater than 0, defaulting to 1")
-		capacity = 1
+// newCache creates a Cache from a validated config.
+func newCache[K comparable, V any](cfg config[K, V]) *Cache[K, V] {
+	if cfg.policy == nil {
+		cfg.policy = lru.New[K]()
+	}
+	if cfg.clock == nil {
+		cfg.clock = clock.Real()
+	}
+	if !cfg.initialSizeSet {
+		cfg.initialSize = 0
+		if cfg.weigher == nil {
+			cfg.initialSize = cfg.capacity
+		}
 	}
-	return &Cache[K, V]{
-		capacity: capacity,
-		policy:   policy,
-		storage:  make(map[K]V, capacity),
+	c := &Cache[K, V]{
+		capacity:  cfg.capacity,
+		policy:    cfg.policy,
+		weigher:   cfg.weigher,
+		clock:     cfg.clock,
+		stats:     cfg.stats,
+		logger:    newLogger(cfg),
+		tracer:    cfg.tracer,
+		name:      cfg.name,
+		storage:   make(map[K]V, cfg.initialSize),
+		listeners: cfg.listeners,
+		reads:     newReadBuffer[K](),
 	}
+	if c.weigher != nil {
+		c.weights = make(map[K]int, cfg.initialSize)
+	}
+	return c
 }
 
 // Set adds or updates a value in the cache.
 func (c *Cache[K, V]) Set(key K, value V) {
+	span := c.startSpan(SpanSet, key)
+	removed := c.set(key, value)
+
+	if span != nil {
+		span.SetAttributes(slog.Int(AttrEvicted, len(removed)))
+		span.End()
+	}
+	c.notify(removed)
+}
+
+// set is Set without tracing or notification. The lock is released even if
+// the weigher panics.
+func (c *Cache[K, V]) set(key K, value V) []removal[K, V] {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	return c.setLocked(key, value)
+}
+
+// unlockAndNotify releases c.mu, then tells the removal listeners about
+// removed. Deferred straight after locking, it keeps the cache usable if a
+// function supplied by the caller panics, and still reports the entries
+// removed before the panic.
+func (c *Cache[K, V]) unlockAndNotify(removed *[]removal[K, V]) {
+	c.mu.Unlock()
+	c.notify(*removed)
+}
+
+// setLocked adds or updates a value, and returns the entries evicted to make
+// room for it. The caller must hold c.mu for writing.
+func (c *Cache[K, V]) setLocked(key K, value V) []removal[K, V] {
+	// Apply earlier hits first, so that the policy sees them in order
+	c.drainReadsLocked()
+
+	weight := c.weigh(key, value)
+	if weight > c.capacity {
+		return c.rejectLocked(key, value)
+	}
 
 	// Check if the key already exists
 	if _, ok := c.storage[key]; ok {
 		// Update the value directly
 		c.storage[key] = value
+		c.setWeightLocked(key, weight)
+		c.stats.RecordUpdate()
 		// Notify the policy of the access
 		c.policy.OnAccess(key)
-		return
+		// A heavier value may no longer fit
+		return c.evictLocked(0)
 	}
 
 	// Check if the cache is at capacity BEFORE adding
-	if len(c.storage) >= c.capacity {
+	removed := c.evictLocked(weight)
+
+	// Add the new key-value pair to storage
+	c.storage[key] = value
+	c.setWeightLocked(key, weight)
+	c.stats.RecordSet()
+	// Notify the policy that a new key was added
+	c.policy.OnAdd(key)
+	return removed
+}
+
+// rejectLocked handles a value too heavy to fit in the cache even on its
+// own: it is stored and at once evicted, leaving every other entry in place.
+// Like any update, it replaces the key's previous value, if any. The caller
+// must hold c.mu for writing.
+func (c *Cache[K, V]) rejectLocked(key K, value V) []removal[K, V] {
+	if _, ok := c.removeLocked(key, Evicted); ok {
+		c.stats.RecordUpdate()
+	} else {
+		c.stats.RecordSet()
+	}
+	return []removal[K, V]{{key, value, Evicted}}
+}
+
+// evictLocked evicts entries via the policy until another extra units of
+// weight fit within the capacity, and returns the evicted entries. The caller
+// must hold c.mu for writing.
+func (c *Cache[K, V]) evictLocked(extra int) []removal[K, V] {
+	if len(c.storage) == 0 || c.weight+extra <= c.capacity {
+		return nil
+	}
+
+	// Bring the policy up to date so that it picks informed victims
+	c.drainReadsLocked()
+
+	var removed []removal[K, V]
+	for len(c.storage) > 0 && c.weight+extra > c.capacity {
 		// Ask the policy for the key to evict
 		keyToEvict := c.policy.OnEvict()
 		// Remove the evicted key from storage
+		evicted, ok := c.storage[keyToEvict]
+		if !ok {
+			// The policy has nothing left to offer that we hold
+			break
+		}
 		delete(c.storage, keyToEvict)
+		c.setWeightLocked(keyToEvict, 0)
+		removed = append(removed, removal[K, V]{keyToEvict, evicted, Evicted})
 	}
+	return removed
+}
 
-	// Add the new key-value pair to storage
-	c.storage[key] = value
-	// Notify the policy that a new key was added
-	c.policy.OnAdd(key)
+// weigh returns the weight of an entry: 1 without a weigher.
+func (c *Cache[K, V]) weigh(key K, value V) int {
+	if c.weigher == nil {
+		return 1
+	}
+	return max(c.weigher(key, value), 0)
+}
+
+// setWeightLocked records the weight of an entry, keeping the total up to
+// date. A weight of 0 is used for entries that have been removed. The caller
+// must hold c.mu for writing.
+func (c *Cache[K, V]) setWeightLocked(key K, weight int) {
+	if c.weigher == nil {
+		// Every entry weighs 1, so the total is simply the number of entries
+		c.weight = len(c.storage)
+		return
+	}
+	c.weight += weight - c.weights[key]
+	if weight == 0 {
+		delete(c.weights, key)
+	} else {
+		c.weights[key] = weight
+	}
 }
 
 // Get retrieves a value from the cache.
+// Hits are passed to the policy in batches rather than one at a time, so
+// concurrent readers do not serialise on the policy's lock.
 func (c *Cache[K, V]) Get(key K) (V, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	if span := c.startSpan(SpanGet, key); span != nil {
+		value, ok := c.get(key)
+		span.SetAttributes(slog.Bool(AttrHit, ok))
+		span.End()
+		return value, ok
+	}
+	return c.get(key)
+}
 
+// get is Get without tracing.
+func (c *Cache[K, V]) get(key K) (V, bool) {
+	c.mu.RLock()
 	// Look up the value directly in the storage map
 	value, ok := c.storage[key]
+	c.mu.RUnlock()
+
 	if !ok {
+		c.stats.RecordMisses(1)
 		var zeroV V
 		return zeroV, false
 	}
+	c.stats.RecordHits(1)
 
-	// If found, notify the policy of the access
-	c.policy.OnAccess(key)
+	// If found, record the access for the policy
+	if c.reads.record(key) && c.mu.TryLock() {
+		c.drainReadsLocked()
+		c.mu.Unlock()
+	}
 
 	// Return the value
 	return value, true
 }
 
+// Resize changes the maximum number of entries, or the maximum total weight,
+// the cache holds. When shrinking, entries are evicted via the policy until
+// the cache fits, and removal listeners are told about them. When growing,
+// the existing entries are kept and more are simply allowed in.
+func (c *Cache[K, V]) Resize(newCapacity int) {
+	if newCapacity <= 0 {
+		if c.logger != nil {
+			c.logger.Warn("cache capacity must be greater than 0, defaulting to 1", "capacity", newCapacity)
+		}
+		newCapacity = 1
+	}
+
+	c.mu.Lock()
+	oldCapacity := c.capacity
+	c.capacity = newCapacity
+	removed := c.evictLocked(0)
+	c.mu.Unlock()
+
+	c.debug("cache resized", "old_capacity", oldCapacity, "capacity", newCapacity, "evicted", len(removed))
+	c.notify(removed)
+}
+
+// GetMany retrieves several values under a single lock acquisition. Keys that
+// are not in the cache are absent from the result. Hits are recorded with the
+// policy in one batch.
+func (c *Cache[K, V]) GetMany(keys []K) map[K]V {
+	found := make(map[K]V, len(keys))
+	hits := 0
+	c.mu.RLock()
+	for _, key := range keys {
+		if value, ok := c.storage[key]; ok {
+			found[key] = value
+			hits++
+		}
+	}
+	c.mu.RUnlock()
+	// Count every key asked for, as Get would, even if it was repeated
+	c.stats.RecordHits(hits)
+	c.stats.RecordMisses(len(keys) - hits)
+
+	full := false
+	for key := range found {
+		if c.reads.record(key) {
+			full = true
+		}
+	}
+	if full && c.mu.TryLock() {
+		c.drainReadsLocked()
+		c.mu.Unlock()
+	}
+	return found
+}
+
+// SetMany adds or updates several values under a single lock acquisition.
+// Entries evicted to make room may include ones added earlier in the same
+// batch, in no particular order.
+func (c *Cache[K, V]) SetMany(items map[K]V) {
+	c.mu.Lock()
+	var removed []removal[K, V]
+	defer c.unlockAndNotify(&removed)
+
+	for key, value := range items {
+		removed = append(removed, c.setLocked(key, value)...)
+	}
+}
+
+// drainReadsLocked passes the recorded hits to the policy. Keys that have
+// left the cache since they were read are skipped. It is called before every
+// other change to the policy, so that hits and writes reach it in order, and
+// costs little when nothing has been recorded. The caller must hold c.mu for
+// writing.
+func (c *Cache[K, V]) drainReadsLocked() {
+	c.reads.drain(func(key K) {
+		if _, ok := c.storage[key]; ok {
+			c.policy.OnAccess(key)
+		}
+	})
+}
+
 // Delete removes a value from the cache.
 func (c *Cache[K, V]) Delete(key K) {
+	span := c.startSpan(SpanDelete, key)
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	// Check if the key exists before trying to delete
-	if _, ok := c.storage[key]; !ok {
-		return
+	r, ok := c.removeLocked(key, Deleted)
+	c.mu.Unlock()
+
+	if span != nil {
+		span.SetAttributes(slog.Bool(AttrHit, ok))
+		span.End()
+	}
+
+	if ok {
+		c.notify([]removal[K, V]{r})
+	}
+}
+
+// DeleteMany removes several values from the cache under a single lock
+// acquisition. Keys that are not in the cache are ignored.
+func (c *Cache[K, V]) DeleteMany(keys []K) {
+	c.mu.Lock()
+	var removed []removal[K, V]
+	for _, key := range keys {
+		if r, ok := c.removeLocked(key, Deleted); ok {
+			removed = append(removed, r)
+		}
+	}
+	c.mu.Unlock()
+
+	c.notify(removed)
+}
+
+// DeleteFunc removes every entry for which pred returns true, and returns the
+// number of entries removed. pred is called with the cache locked, so it must
+// not call back into the cache.
+func (c *Cache[K, V]) DeleteFunc(pred func(key K, value V) bool) int {
+	c.mu.Lock()
+	var removed []removal[K, V]
+	defer c.unlockAndNotify(&removed)
+
+	for key, value := range c.storage {
+		if pred(key, value) {
+			r, _ := c.removeLocked(key, Deleted)
+			removed = append(removed, r)
+		}
+	}
+	return len(removed)
+}
+
+// Clear removes every entry from the cache and resets the eviction policy,
+// keeping the same *Cache usable by everyone that holds it. Removal listeners
+// are told about each entry with the Cleared reason.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	removed := make([]removal[K, V], 0, len(c.storage))
+	for key, value := range c.storage {
+		c.policy.OnRemove(key)
+		removed = append(removed, removal[K, V]{key, value, Cleared})
 	}
+	clear(c.storage)
+	clear(c.weights)
+	c.weight = 0
+	// Discard buffered hits; none of their keys are in the cache any more
+	c.drainReadsLocked()
+	c.mu.Unlock()
+
+	c.notify(removed)
+}
+
+// removeLocked deletes a key from storage and the policy. It returns the
+// removed entry and whether the key was present. The caller must hold c.mu
+// for writing.
+func (c *Cache[K, V]) removeLocked(key K, reason RemovalReason) (removal[K, V], bool) {
+	value, ok := c.storage[key]
+	if !ok {
+		return removal[K, V]{}, false
+	}
+	c.drainReadsLocked()
 
 	// Delete from the storage map
 	delete(c.storage, key)
+	c.setWeightLocked(key, 0)
 
 	// Notify the policy of the removal
 	c.policy.OnRemove(key)
+	return removal[K, V]{key, value, reason}, true
+}
+
+// AddRemovalListener registers a listener that is told about every entry
+// that is evicted from or deleted from the cache.
+func (c *Cache[K, V]) AddRemovalListener(listener RemovalListener[K, V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.listeners = append(c.listeners, listener)
+}
+
+// Close runs the functions registered with OnClose, e.g. to unregister the
+// cache from a metrics registry. The cache holds no other resources, and it
+// remains usable afterwards.
+func (c *Cache[K, V]) Close() {
+	c.mu.Lock()
+	c.closed = true
+	hooks := c.closeHooks
+	c.closeHooks = nil
+	c.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
 }
 
-// Static assertion to ensure *Cache satisfies the Cacheable interface.
-var _ Cacheable[any, any] = (*Cache[any, any])(nil)
+// OnClose registers fn to be called once, when the cache is closed. If the
+// cache is already closed, fn is called straight away.
+func (c *Cache[K, V]) OnClose(fn func()) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		fn()
+		return
+	}
+	c.closeHooks = append(c.closeHooks, fn)
+	c.mu.Unlock()
+}
+
+// notify reports removed entries to the registered listeners. It must be
+// called without holding c.mu.
+func (c *Cache[K, V]) notify(removed []removal[K, V]) {
+	if len(removed) == 0 {
+		return
+	}
+	for _, r := range removed {
+		c.stats.RecordRemoval(r.reason)
+	}
+	if c.debugEnabled() {
+		for _, r := range removed {
+			if r.reason == Evicted {
+				c.logger.Debug("cache eviction", "key", r.key)
+			}
+		}
+	}
+
+	c.mu.RLock()
+	listeners := c.listeners
+	c.mu.RUnlock()
+
+	for _, r := range removed {
+		for _, listener := range listeners {
+			listener(r.key, r.value, r.reason)
+		}
+	}
+}
+
+// Static assertions to ensure *Cache satisfies the Cacheable, Batcher, Computer and Peeker interfaces.
+var (
+	_ Cacheable[any, any] = (*Cache[any, any])(nil)
+	_ Batcher[any, any]   = (*Cache[any, any])(nil)
+	_ Computer[any, any]  = (*Cache[any, any])(nil)
+	_ Peeker[any, any]    = (*Cache[any, any])(nil)
+)
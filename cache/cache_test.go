@@ -1,13 +1,16 @@
 package cache_test
 
 import (
+	"maps"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/Varun0157/in-mem-cache/cache"
 	"github.com/Varun0157/in-mem-cache/cache/policies"
+	"github.com/Varun0157/in-mem-cache/workload"
 )
 
 func TestCache_With_LRU_Policy(t *testing.T) {
@@ -100,6 +103,42 @@ func BenchmarkCacheSet(b *testing.B) {
 	}
 }
 
+func BenchmarkCacheGetParallel(b *testing.B) {
+	c := cache.New[int, int](1024, policies.NewLRU[int]())
+	for i := range 1000 {
+		c.Set(i, i)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			c.Get(i % 1000)
+			i++
+		}
+	})
+}
+
+func BenchmarkCacheGetParallelZipf(b *testing.B) {
+	// Most readers hit the same few keys, as in real traffic
+	const keys = 1 << 16
+	c := cache.New[uint64, uint64](keys, policies.NewLRU[uint64]())
+	for key := range uint64(keys) {
+		c.Set(key, key)
+	}
+	trace := workload.Keys(workload.NewZipf(keys, workload.DefaultSkew, 1), keys)
+
+	var goroutines atomic.Uint64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := goroutines.Add(1) * 4099
+		for pb.Next() {
+			c.Get(trace[i&(keys-1)])
+			i++
+		}
+	})
+}
+
 func BenchmarkCacheGet(b *testing.B) {
 	c := cache.New[int, int](1024, policies.NewLRU[int]())
 	for i := range 1000 {
@@ -245,3 +284,313 @@ func TestCache_ConcurrentMixedOperations(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestCache_RemovalListener(t *testing.T) {
+	c := cache.New[string, int](2, policies.NewFIFO[string]())
+
+	type event struct {
+		key    string
+		value  int
+		reason cache.RemovalReason
+	}
+	var events []event
+	c.AddRemovalListener(func(key string, value int, reason cache.RemovalReason) {
+		events = append(events, event{key, value, reason})
+	})
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("b", 20) // Updating does not remove anything
+	c.Set("c", 3)  // Evicts "a"
+	c.Delete("b")
+	c.Delete("missing") // No-op, no event
+
+	require.Equal(t, []event{
+		{"a", 1, cache.Evicted},
+		{"b", 20, cache.Deleted},
+	}, events)
+}
+
+func TestCache_RemovalListenerCanCallBack(t *testing.T) {
+	c := cache.New[string, int](1, policies.NewLRU[string]())
+
+	// Listeners run after the lock is released, so re-entering is safe
+	c.AddRemovalListener(func(key string, value int, reason cache.RemovalReason) {
+		if reason == cache.Evicted {
+			c.Get(key)
+		}
+	})
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	val, found := c.Get("b")
+	require.True(t, found)
+	require.Equal(t, 2, val)
+}
+
+// countingPolicy wraps a policy and counts the accesses passed to it.
+type countingPolicy[K comparable] struct {
+	cache.EvictionPolicy[K]
+	accesses atomic.Int64
+}
+
+func (p *countingPolicy[K]) OnAccess(key K) {
+	p.accesses.Add(1)
+	p.EvictionPolicy.OnAccess(key)
+}
+
+func TestCache_BufferedAccess(t *testing.T) {
+	policy := &countingPolicy[string]{EvictionPolicy: policies.NewLRU[string]()}
+	c := cache.New[string, int](2, policy)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// Hits are buffered rather than passed to the policy straight away
+	c.Get("a")
+	require.Equal(t, int64(0), policy.accesses.Load())
+
+	// The buffer is drained before choosing a victim, so "a" counts as recent
+	c.Set("c", 3)
+	require.Equal(t, int64(1), policy.accesses.Load())
+
+	_, found := c.Get("a")
+	require.True(t, found)
+	_, found = c.Get("b")
+	require.False(t, found)
+}
+
+func TestCache_BufferedAccessKeepsOrder(t *testing.T) {
+	// Hits land on random stripes, so repeat to cover many placements
+	for range 1000 {
+		c := cache.New[string, int](2, policies.NewLRU[string]())
+		c.Set("a", 1)
+		c.Set("b", 2)
+		c.Get("a")
+		c.Get("b")
+		c.Set("c", 3) // Evicts "a", the least recently used
+
+		require.False(t, c.Contains("a"))
+		require.True(t, c.Contains("b"))
+	}
+}
+
+func TestCache_BufferedAccessBeforeWrite(t *testing.T) {
+	c := cache.New[string, int](2, policies.NewLRU[string]())
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Set("b", 3) // Updating "b" makes it more recent than the hit on "a"
+	c.Set("c", 4)
+
+	require.False(t, c.Contains("a"))
+	require.True(t, c.Contains("b"))
+}
+
+func TestCache_BufferedAccessDrainsWhenFull(t *testing.T) {
+	policy := &countingPolicy[string]{EvictionPolicy: policies.NewLRU[string]()}
+	c := cache.New[string, int](2, policy)
+
+	c.Set("a", 1)
+
+	// Enough hits fill a stripe and trigger a drain
+	for range 1000 {
+		c.Get("a")
+	}
+	require.Positive(t, policy.accesses.Load())
+}
+
+func TestCache_Clear(t *testing.T) {
+	c := cache.New[string, int](2, policies.NewLRU[string]())
+
+	var cleared []string
+	c.AddRemovalListener(func(key string, _ int, reason cache.RemovalReason) {
+		if reason == cache.Cleared {
+			cleared = append(cleared, key)
+		}
+	})
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Clear()
+
+	require.Equal(t, 0, c.Len())
+	require.ElementsMatch(t, []string{"a", "b"}, cleared)
+
+	// The same cache keeps working, with a fresh policy
+	c.Set("c", 3)
+	c.Set("d", 4)
+	c.Set("e", 5) // Evicts "c"
+
+	_, found := c.Get("c")
+	require.False(t, found)
+	require.ElementsMatch(t, []string{"d", "e"}, c.Keys())
+}
+
+func TestCache_DeleteMany(t *testing.T) {
+	c := cache.New[string, int](5, policies.NewLRU[string]())
+
+	var deleted []string
+	c.AddRemovalListener(func(key string, _ int, reason cache.RemovalReason) {
+		require.Equal(t, cache.Deleted, reason)
+		deleted = append(deleted, key)
+	})
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+
+	c.DeleteMany([]string{"a", "c", "missing"})
+	require.ElementsMatch(t, []string{"a", "c"}, deleted)
+	require.Equal(t, []string{"b"}, c.Keys())
+}
+
+func TestCache_DeleteFunc(t *testing.T) {
+	c := cache.New[int, int](10, policies.NewFIFO[int]())
+	for i := range 10 {
+		c.Set(i, i*i)
+	}
+
+	// Remove every entry with an odd value
+	removed := c.DeleteFunc(func(_ int, value int) bool {
+		return value%2 == 1
+	})
+	require.Equal(t, 5, removed)
+	require.ElementsMatch(t, []int{0, 2, 4, 6, 8}, c.Keys())
+
+	// The policy forgot the removed keys, so eviction skips straight past them
+	for i := 10; i < 15; i++ {
+		c.Set(i, i)
+	}
+	c.Set(15, 15) // Evicts 0
+	require.False(t, c.Contains(0))
+	require.True(t, c.Contains(2))
+}
+
+func TestCache_GetMany(t *testing.T) {
+	c := cache.New[string, int](3, policies.NewLRU[string]())
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+
+	found := c.GetMany([]string{"a", "c", "missing"})
+	require.Equal(t, map[string]int{"a": 1, "c": 3}, found)
+
+	// The batch counts as an access to "a" and "c", leaving "b" to be evicted
+	c.Set("d", 4)
+	require.False(t, c.Contains("b"))
+	require.True(t, c.Contains("a"))
+	require.True(t, c.Contains("c"))
+}
+
+func TestCache_SetMany(t *testing.T) {
+	c := cache.New[string, int](3, policies.NewFIFO[string]())
+	c.Set("a", 1)
+
+	var evicted []string
+	c.AddRemovalListener(func(key string, _ int, reason cache.RemovalReason) {
+		if reason == cache.Evicted {
+			evicted = append(evicted, key)
+		}
+	})
+
+	c.SetMany(map[string]int{"a": 10, "b": 2, "c": 3})
+	require.Equal(t, map[string]int{"a": 10, "b": 2, "c": 3}, maps.Collect(c.All()))
+	require.Empty(t, evicted)
+
+	// A batch larger than the room left evicts to make space
+	c.SetMany(map[string]int{"d": 4, "e": 5})
+	require.Equal(t, 3, c.Len())
+	require.Len(t, evicted, 2)
+	require.Contains(t, evicted, "a")
+}
+
+func BenchmarkCacheGetMany(b *testing.B) {
+	c := cache.New[int, int](1024, policies.NewLRU[int]())
+	keys := make([]int, 100)
+	for i := range 1000 {
+		c.Set(i, i)
+	}
+	for i := range keys {
+		keys[i] = i * 7 % 1000
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.GetMany(keys)
+	}
+}
+
+func TestCache_ResizeShrink(t *testing.T) {
+	c := cache.New[string, int](4, policies.NewLRU[string]())
+
+	var evicted []string
+	c.AddRemovalListener(func(key string, _ int, reason cache.RemovalReason) {
+		require.Equal(t, cache.Evicted, reason)
+		evicted = append(evicted, key)
+	})
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Set("d", 4)
+	c.Get("a")
+
+	// Shrinking evicts the least recently used entries until the cache fits
+	c.Resize(2)
+	require.Equal(t, 2, c.Capacity())
+	require.Equal(t, []string{"b", "c"}, evicted)
+	require.ElementsMatch(t, []string{"a", "d"}, c.Keys())
+
+	// The new capacity is enforced from now on
+	c.Set("e", 5)
+	require.Equal(t, 2, c.Len())
+}
+
+func TestCache_ResizeGrow(t *testing.T) {
+	c := cache.New[string, int](2, policies.NewFIFO[string]())
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// Growing keeps the warm contents and makes room for more
+	c.Resize(3)
+	c.Set("c", 3)
+	require.ElementsMatch(t, []string{"a", "b", "c"}, c.Keys())
+
+	c.Set("d", 4) // Evicts "a"
+	require.False(t, c.Contains("a"))
+}
+
+func TestCache_ResizeZero(t *testing.T) {
+	// Should default to capacity 1
+	c := cache.New[string, int](2, policies.NewLRU[string]())
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	c.Resize(0)
+	require.Equal(t, 1, c.Capacity())
+	require.Equal(t, []string{"b"}, c.Keys())
+}
+
+func TestCache_NilPolicyDefaultsToLRU(t *testing.T) {
+	c := cache.New[string, int](2, nil)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Set("c", 3) // Evicts "b", the least recently used
+
+	_, found := c.Get("b")
+	require.False(t, found)
+	_, found = c.Get("a")
+	require.True(t, found)
+
+	// The same holds for caches built from options without a policy
+	opts, err := cache.NewWithOptions(cache.WithCapacity[string, int](1))
+	require.NoError(t, err)
+	opts.Set("a", 1)
+	opts.Set("b", 2)
+	require.Equal(t, []string{"b"}, opts.Keys())
+}
@@ -0,0 +1,95 @@
+package cachetest
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/policies"
+	"github.com/Varun0157/in-mem-cache/workload"
+)
+
+// The dimensions of RunMatrix, besides the registered policies.
+var (
+	MatrixCapacities  = []int{1_000, 100_000}
+	MatrixWriteRatios = []float64{0, 0.1, 0.5}
+)
+
+// matrixTraceLen is the number of operations generated for each cell of the
+// matrix. It is a power of two, so goroutines can index it with a mask.
+const matrixTraceLen = 1 << 20
+
+// NewCacheFunc creates a cache with the given eviction policy and capacity,
+// for RunMatrix.
+type NewCacheFunc func(policy cache.EvictionPolicy[uint64], capacity int) cache.Cacheable[uint64, uint64]
+
+// RunMatrix benchmarks the caches made by newCache under concurrent load,
+// for every capacity in MatrixCapacities, every write ratio in
+// MatrixWriteRatios and every policy registered in package policies, in
+// sub-benchmarks named like "capacity=1000/writes=10%/lru".
+//
+// Each cell replays a Zipfian key stream over ten times as many keys as the
+// cache holds, from every goroutine of b.RunParallel. Reads that miss store
+// the key, as a cache-aside client would. Besides ns/op and allocations,
+// each cell reports the hit ratio of its reads.
+func RunMatrix(b *testing.B, newCache NewCacheFunc) {
+	for _, capacity := range MatrixCapacities {
+		b.Run(fmt.Sprintf("capacity=%d", capacity), func(b *testing.B) {
+			for _, ratio := range MatrixWriteRatios {
+				b.Run(fmt.Sprintf("writes=%.0f%%", 100*ratio), func(b *testing.B) {
+					keys := workload.NewZipf(10*capacity, workload.DefaultSkew, 1)
+					ops := workload.Ops(workload.NewMix(keys, ratio, 1), matrixTraceLen)
+					for _, name := range policies.Names() {
+						newPolicy, err := policies.Lookup[uint64](name)
+						if err != nil {
+							b.Fatal(err)
+						}
+						b.Run(name, func(b *testing.B) {
+							runCell(b, newCache(newPolicy(), capacity), capacity, ops)
+						})
+					}
+				})
+			}
+		})
+	}
+}
+
+// runCell runs one cell of the matrix against c.
+func runCell(b *testing.B, c cache.Cacheable[uint64, uint64], capacity int, ops []workload.Op) {
+	// Start warm, with the most popular keys.
+	for key := range uint64(capacity) {
+		c.Set(key, key)
+	}
+
+	var goroutines, reads, hits atomic.Uint64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		// Spread the goroutines over the trace, so they do not move in
+		// lockstep.
+		i := goroutines.Add(1) * 7919 * 64
+		var localReads, localHits uint64
+		for pb.Next() {
+			op := ops[i&(matrixTraceLen-1)]
+			i++
+			if op.Write {
+				c.Set(op.Key, op.Key)
+				continue
+			}
+			localReads++
+			if _, ok := c.Get(op.Key); ok {
+				localHits++
+			} else {
+				c.Set(op.Key, op.Key)
+			}
+		}
+		reads.Add(localReads)
+		hits.Add(localHits)
+	})
+	b.StopTimer()
+
+	if n := reads.Load(); n > 0 {
+		b.ReportMetric(float64(hits.Load())/float64(n), "hit-ratio")
+	}
+}
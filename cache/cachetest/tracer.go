@@ -0,0 +1,104 @@
+// Package cachetest provides test doubles for the hooks of package cache,
+// and a benchmark harness for Cacheable implementations.
+package cachetest
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+)
+
+// Span is a span recorded by a Tracer.
+type Span struct {
+	Name  string
+	Attrs map[string]slog.Value
+	Err   error // The last error recorded, if any
+	Ended bool
+
+	tracer *Tracer
+}
+
+// Tracer is a cache.Tracer that records every span it starts, for tests to
+// inspect. It is safe for concurrent use.
+type Tracer struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+// NewTracer creates a Tracer with no spans.
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+// Start records a new span.
+func (t *Tracer) Start(name string, attrs ...slog.Attr) cache.Span {
+	span := &Span{Name: name, Attrs: make(map[string]slog.Value), tracer: t}
+	span.SetAttributes(attrs...)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.spans = append(t.spans, span)
+	return span
+}
+
+// Spans returns snapshots of the spans started so far, in order. Only the
+// spans handed to the cache are live; the snapshots must not be ended or
+// modified through their methods.
+func (t *Tracer) Spans() []Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	spans := make([]Span, len(t.spans))
+	for i, span := range t.spans {
+		spans[i] = *span
+		spans[i].Attrs = make(map[string]slog.Value, len(span.Attrs))
+		for key, value := range span.Attrs {
+			spans[i].Attrs[key] = value
+		}
+		spans[i].tracer = nil
+	}
+	return spans
+}
+
+// Reset forgets the spans started so far.
+func (t *Tracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.spans = nil
+}
+
+// SetAttributes adds attributes to the span, replacing any with the same key.
+func (s *Span) SetAttributes(attrs ...slog.Attr) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	for _, attr := range attrs {
+		s.Attrs[attr.Key] = attr.Value
+	}
+}
+
+// RecordError records err on the span.
+func (s *Span) RecordError(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.Err = err
+}
+
+// End marks the span as ended. It panics if the span has already ended, to
+// catch double-ended spans in tests.
+func (s *Span) End() {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	if s.Ended {
+		panic("cachetest: span " + s.Name + " ended twice")
+	}
+	s.Ended = true
+}
+
+// Static assertion to ensure *Tracer satisfies the cache.Tracer interface.
+var _ cache.Tracer = (*Tracer)(nil)
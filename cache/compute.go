@@ -0,0 +1,112 @@
+package cache
+
+// Op tells Compute what to do with the value returned by its function.
+type Op int
+
+const (
+	// OpStore stores the returned value, adding the entry if it was absent.
+	OpStore Op = iota
+	// OpDelete removes the entry, if it exists.
+	OpDelete
+	// OpKeep leaves the cache unchanged.
+	OpKeep
+)
+
+// Compute atomically reads, modifies and writes the entry for key. fn is
+// given the current value and whether it exists, and returns a new value and
+// what to do with it. Compute returns the value held afterwards and whether
+// the entry exists.
+//
+// The cache stays locked while fn runs, so fn must not call back into it.
+func (c *Cache[K, V]) Compute(key K, fn func(old V, exists bool) (V, Op)) (V, bool) {
+	c.mu.Lock()
+	var removed []removal[K, V]
+	defer c.unlockAndNotify(&removed)
+
+	old, exists := c.storage[key]
+	value, op := fn(old, exists)
+	switch op {
+	case OpStore:
+		removed = c.setLocked(key, value)
+	case OpDelete:
+		if r, ok := c.removeLocked(key, Deleted); ok {
+			removed = append(removed, r)
+		}
+		value, exists = old, false
+	default:
+		if exists {
+			// Reading the entry counts as an access, as with Get
+			c.drainReadsLocked()
+			c.policy.OnAccess(key)
+		}
+		value = old
+	}
+
+	if op == OpStore {
+		return value, true
+	}
+	if !exists {
+		var zeroV V
+		return zeroV, false
+	}
+	return value, true
+}
+
+// SetIfAbsent stores value only if key is not already in the cache. It
+// returns the value now held for key, and whether it was already present.
+func (c *Cache[K, V]) SetIfAbsent(key K, value V) (actual V, loaded bool) {
+	return SetIfAbsent(c, key, value)
+}
+
+// CompareAndSwap replaces the value for key with new, but only if the key
+// exists and its current value equals old according to equal. If equal is
+// nil, values are compared with ==, which panics if V holds values that are
+// not comparable. It reports whether the swap happened.
+func (c *Cache[K, V]) CompareAndSwap(key K, old, new V, equal func(a, b V) bool) bool {
+	return CompareAndSwap(c, key, old, new, equal)
+}
+
+// Update atomically replaces the value for key with fn applied to it, if the
+// key exists. It returns the new value and whether the key existed. The cache
+// stays locked while fn runs, so fn must not call back into it.
+func (c *Cache[K, V]) Update(key K, fn func(old V) V) (V, bool) {
+	return Update(c, key, fn)
+}
+
+// SetIfAbsent implements SetIfAbsent on top of any Computer.
+func SetIfAbsent[K comparable, V any](c Computer[K, V], key K, value V) (actual V, loaded bool) {
+	actual, _ = c.Compute(key, func(old V, exists bool) (V, Op) {
+		if exists {
+			loaded = true
+			return old, OpKeep
+		}
+		return value, OpStore
+	})
+	return actual, loaded
+}
+
+// CompareAndSwap implements CompareAndSwap on top of any Computer.
+func CompareAndSwap[K comparable, V any](c Computer[K, V], key K, old, new V, equal func(a, b V) bool) bool {
+	if equal == nil {
+		equal = func(a, b V) bool { return any(a) == any(b) }
+	}
+	swapped := false
+	c.Compute(key, func(current V, exists bool) (V, Op) {
+		if !exists || !equal(current, old) {
+			return current, OpKeep
+		}
+		swapped = true
+		return new, OpStore
+	})
+	return swapped
+}
+
+// Update implements Update on top of any Computer.
+func Update[K comparable, V any](c Computer[K, V], key K, fn func(old V) V) (V, bool) {
+	return c.Compute(key, func(old V, exists bool) (V, Op) {
+		if !exists {
+			return old, OpKeep
+		}
+		return fn(old), OpStore
+	})
+}
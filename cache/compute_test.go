@@ -0,0 +1,154 @@
+package cache_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/policies"
+)
+
+func TestCache_Compute(t *testing.T) {
+	c := cache.New[string, int](2, policies.NewLRU[string]())
+
+	// Create an entry
+	val, ok := c.Compute("a", func(old int, exists bool) (int, cache.Op) {
+		require.False(t, exists)
+		return 1, cache.OpStore
+	})
+	require.True(t, ok)
+	require.Equal(t, 1, val)
+
+	// Modify it
+	val, ok = c.Compute("a", func(old int, exists bool) (int, cache.Op) {
+		require.True(t, exists)
+		return old + 1, cache.OpStore
+	})
+	require.True(t, ok)
+	require.Equal(t, 2, val)
+
+	// Leave it alone
+	val, ok = c.Compute("a", func(old int, exists bool) (int, cache.Op) {
+		return 100, cache.OpKeep
+	})
+	require.True(t, ok)
+	require.Equal(t, 2, val)
+
+	// Remove it
+	_, ok = c.Compute("a", func(old int, exists bool) (int, cache.Op) {
+		return 0, cache.OpDelete
+	})
+	require.False(t, ok)
+	require.False(t, c.Contains("a"))
+
+	// Keeping an absent entry leaves it absent
+	_, ok = c.Compute("b", func(old int, exists bool) (int, cache.Op) {
+		return 0, cache.OpKeep
+	})
+	require.False(t, ok)
+	require.Equal(t, 0, c.Len())
+}
+
+func TestCache_SetIfAbsent(t *testing.T) {
+	c := cache.New[string, int](2, policies.NewLRU[string]())
+
+	actual, loaded := c.SetIfAbsent("a", 1)
+	require.False(t, loaded)
+	require.Equal(t, 1, actual)
+
+	actual, loaded = c.SetIfAbsent("a", 2)
+	require.True(t, loaded)
+	require.Equal(t, 1, actual)
+}
+
+func TestCache_CompareAndSwap(t *testing.T) {
+	c := cache.New[string, int](2, policies.NewLRU[string]())
+	c.Set("a", 1)
+
+	require.False(t, c.CompareAndSwap("a", 2, 3, nil))
+	require.True(t, c.CompareAndSwap("a", 1, 3, nil))
+	require.False(t, c.CompareAndSwap("missing", 0, 1, nil))
+
+	val, _ := c.Get("a")
+	require.Equal(t, 3, val)
+	require.False(t, c.Contains("missing"))
+}
+
+func TestCache_CompareAndSwapNonComparable(t *testing.T) {
+	c := cache.New[string, []string](2, policies.NewLRU[string]())
+	c.Set("a", []string{"x"})
+
+	require.True(t, c.CompareAndSwap("a", []string{"x"}, []string{"y"}, slices.Equal[[]string]))
+
+	val, _ := c.Get("a")
+	require.Equal(t, []string{"y"}, val)
+}
+
+func TestCache_PanicsReleaseLock(t *testing.T) {
+	c, err := cache.NewWithOptions(
+		cache.WithCapacity[string, []int](2),
+		cache.WithWeigher(func(key string, _ []int) int {
+			if key == "bad" {
+				panic("bad weight")
+			}
+			return 1
+		}),
+	)
+	require.NoError(t, err)
+	c.Set("a", []int{1})
+
+	// A nil equal cannot compare slices
+	require.Panics(t, func() { c.CompareAndSwap("a", []int{1}, []int{2}, nil) })
+	require.Panics(t, func() {
+		c.Compute("a", func([]int, bool) ([]int, cache.Op) { panic("compute") })
+	})
+	require.Panics(t, func() { c.DeleteFunc(func(string, []int) bool { panic("pred") }) })
+	require.Panics(t, func() { c.Set("bad", nil) })
+	require.Panics(t, func() { c.SetMany(map[string][]int{"bad": nil}) })
+
+	// The cache is still usable
+	val, found := c.Get("a")
+	require.True(t, found)
+	require.Equal(t, []int{1}, val)
+	c.Set("b", []int{2})
+	require.Equal(t, 2, c.Len())
+}
+
+func TestCache_Update(t *testing.T) {
+	c := cache.New[string, int](2, policies.NewLRU[string]())
+
+	_, ok := c.Update("a", func(old int) int { return old + 1 })
+	require.False(t, ok)
+	require.False(t, c.Contains("a"))
+
+	c.Set("a", 1)
+	val, ok := c.Update("a", func(old int) int { return old + 1 })
+	require.True(t, ok)
+	require.Equal(t, 2, val)
+}
+
+func TestCache_ConcurrentUpdate(t *testing.T) {
+	c := cache.New[string, int](10, policies.NewLRU[string]())
+	c.Set("counter", 0)
+	var wg sync.WaitGroup
+	numGoroutines := 10
+	numOperations := 1000
+
+	for range numGoroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range numOperations {
+				c.Update("counter", func(old int) int { return old + 1 })
+			}
+		}()
+	}
+	wg.Wait()
+
+	// No increments are lost, unlike with Get followed by Set
+	val, _ := c.Get("counter")
+	require.Equal(t, numGoroutines*numOperations, val)
+}
@@ -0,0 +1,102 @@
+package cache
+
+import "iter"
+
+// Peek retrieves a value without recording an access with the eviction policy.
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	value, ok := c.storage[key]
+	return value, ok
+}
+
+// Contains reports whether a key is in the cache, without recording an
+// access with the eviction policy.
+func (c *Cache[K, V]) Contains(key K) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.storage[key]
+	return ok
+}
+
+// Len returns the number of entries in the cache.
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.storage)
+}
+
+// Weight returns the total weight of the entries in the cache. Without a
+// weigher every entry weighs 1, so it matches Len.
+func (c *Cache[K, V]) Weight() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.weight
+}
+
+// Capacity returns the maximum number of entries, or the maximum total
+// weight, the cache holds.
+func (c *Cache[K, V]) Capacity() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.capacity
+}
+
+// Keys returns the keys currently in the cache, in no particular order.
+func (c *Cache[K, V]) Keys() []K {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	keys := make([]K, 0, len(c.storage))
+	for key := range c.storage {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Range calls fn for each entry in the cache, in no particular order, until
+// fn returns false. It iterates over a snapshot taken when Range is called:
+// fn may call back into the cache, and changes made during the iteration are
+// not reflected in it. Range does not record accesses with the eviction policy.
+func (c *Cache[K, V]) Range(fn func(key K, value V) bool) {
+	for key, value := range c.All() {
+		if !fn(key, value) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over a snapshot of the entries in the cache, with
+// the same semantics as Range.
+func (c *Cache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, e := range c.snapshot() {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// entry is a key-value pair copied out of the cache.
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// snapshot copies the entries out of the cache under the read lock.
+func (c *Cache[K, V]) snapshot() []entry[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entries := make([]entry[K, V], 0, len(c.storage))
+	for key, value := range c.storage {
+		entries = append(entries, entry[K, V]{key, value})
+	}
+	return entries
+}
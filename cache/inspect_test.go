@@ -0,0 +1,106 @@
+package cache_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/policies"
+)
+
+func TestCache_PeekDoesNotTouchPolicy(t *testing.T) {
+	c := cache.New[string, int](2, policies.NewLRU[string]())
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// Peeking at "a" must not make it more recently used than "b"
+	val, found := c.Peek("a")
+	require.True(t, found)
+	require.Equal(t, 1, val)
+
+	c.Set("c", 3) // Evicts "a"
+	require.False(t, c.Contains("a"))
+	require.True(t, c.Contains("b"))
+
+	_, found = c.Peek("a")
+	require.False(t, found)
+}
+
+func TestCache_LenAndCapacity(t *testing.T) {
+	c := cache.New[string, int](3, policies.NewFIFO[string]())
+	require.Equal(t, 0, c.Len())
+	require.Equal(t, 3, c.Capacity())
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	require.Equal(t, 2, c.Len())
+
+	c.Set("c", 3)
+	c.Set("d", 4)
+	require.Equal(t, 3, c.Len())
+	require.Equal(t, 3, c.Capacity())
+}
+
+func TestCache_Keys(t *testing.T) {
+	c := cache.New[string, int](3, policies.NewLRU[string]())
+	require.Empty(t, c.Keys())
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	require.ElementsMatch(t, []string{"a", "b"}, c.Keys())
+}
+
+func TestCache_Range(t *testing.T) {
+	c := cache.New[string, int](3, policies.NewLRU[string]())
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+
+	seen := make(map[string]int)
+	c.Range(func(key string, value int) bool {
+		seen[key] = value
+		return true
+	})
+	require.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3}, seen)
+
+	// Returning false stops the iteration
+	calls := 0
+	c.Range(func(string, int) bool {
+		calls++
+		return false
+	})
+	require.Equal(t, 1, calls)
+}
+
+func TestCache_RangeOverSnapshot(t *testing.T) {
+	c := cache.New[string, int](3, policies.NewLRU[string]())
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// The callback may modify the cache without deadlocking or
+	// affecting the entries being iterated over
+	seen := 0
+	c.Range(func(key string, _ int) bool {
+		c.Delete(key)
+		c.Set(key+key, 0)
+		seen++
+		return true
+	})
+	require.Equal(t, 2, seen)
+	require.ElementsMatch(t, []string{"aa", "bb"}, c.Keys())
+}
+
+func TestCache_All(t *testing.T) {
+	c := cache.New[string, int](3, policies.NewLRU[string]())
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, maps.Collect(c.All()))
+
+	for range c.All() {
+		break // Stopping early must not panic
+	}
+}
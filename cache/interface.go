@@ -6,4 +6,31 @@ type Cacheable[K comparable, V any] interface {
 	Get(key K) (V, bool)
 	Set(key K, value V)
 	Delete(key K)
+
+	// AddRemovalListener registers a listener that is told about every
+	// entry that leaves the cache, whatever the reason.
+	AddRemovalListener(listener RemovalListener[K, V])
+}
+
+// Peeker is implemented by caches that can look up an entry without side
+// effects: the access is not seen by the eviction policy, statistics or any
+// other hook. Decorators use it when the cache they wrap supports it.
+type Peeker[K comparable, V any] interface {
+	Peek(key K) (V, bool)
+	Contains(key K) bool
+}
+
+// Batcher is implemented by caches that can read and write several entries
+// under a single lock acquisition. Decorators use it when the cache they wrap
+// supports it.
+type Batcher[K comparable, V any] interface {
+	GetMany(keys []K) map[K]V
+	SetMany(items map[K]V)
+}
+
+// Computer is implemented by caches that can read, modify and write an entry
+// atomically. See Cache.Compute for the semantics. SetIfAbsent,
+// CompareAndSwap and Update work with any Computer.
+type Computer[K comparable, V any] interface {
+	Compute(key K, fn func(old V, exists bool) (V, Op)) (V, bool)
 }
@@ -0,0 +1,46 @@
+package cache
+
+// RemovalReason describes why an entry left a cache.
+type RemovalReason int
+
+const (
+	// Evicted means the eviction policy removed the entry to make room.
+	Evicted RemovalReason = iota
+	// Deleted means the entry was removed explicitly, e.g. via Delete.
+	Deleted
+	// Expired means a decorator such as ttl.Cache removed the entry because
+	// its time ran out.
+	Expired
+	// Cleared means the entry was removed because the whole cache was cleared.
+	Cleared
+)
+
+// String returns a lower-case name for the reason.
+func (r RemovalReason) String() string {
+	switch r {
+	case Evicted:
+		return "evicted"
+	case Deleted:
+		return "deleted"
+	case Expired:
+		return "expired"
+	case Cleared:
+		return "cleared"
+	default:
+		return "unknown"
+	}
+}
+
+// RemovalListener is notified after an entry has been removed from a cache.
+// Listeners are called after the cache has released its lock, on the
+// goroutine that caused the removal, so they may safely call back into the
+// cache. They should return quickly.
+type RemovalListener[K comparable, V any] func(key K, value V, reason RemovalReason)
+
+// removal records an entry that left the cache, to be reported to listeners
+// once the lock is released.
+type removal[K comparable, V any] struct {
+	key    K
+	value  V
+	reason RemovalReason
+}
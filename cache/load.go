@@ -0,0 +1,35 @@
+package cache
+
+// GetOrLoad returns the value for key, calling loader to produce it on a
+// miss. A loaded value is stored unless another one was stored for key in
+// the meantime, in which case that one is returned instead. If loader fails,
+// nothing is stored and its error is returned.
+//
+// loader runs without the cache locked, so it may take its time, but
+// concurrent misses for the same key may each call it.
+func (c *Cache[K, V]) GetOrLoad(key K, loader func(key K) (V, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	span := c.startSpan(SpanLoad, key)
+	start := c.clock.Now()
+	value, err := loader(key)
+	elapsed := c.clock.Now().Sub(start)
+	if span != nil {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}
+	if err != nil {
+		c.stats.RecordLoadFailure(elapsed)
+		c.debug("cache load failed", "key", key, "error", err, "duration", elapsed)
+		var zeroV V
+		return zeroV, err
+	}
+	c.stats.RecordLoadSuccess(elapsed)
+
+	actual, _ := c.SetIfAbsent(key, value)
+	return actual, nil
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// newLogger returns the configured logger with the cache's attributes, or
+// nil if logging is off.
+func newLogger[K comparable, V any](cfg config[K, V]) *slog.Logger {
+	if cfg.logger == nil {
+		return nil
+	}
+	logger := cfg.logger.With("policy", policyName(cfg.policy))
+	if cfg.name != "" {
+		logger = logger.With("cache", cfg.name)
+	}
+	return logger
+}
+
+// policyName describes a policy in log records: by its String method if it
+// has one, as the built-in policies do, and by its type otherwise.
+func policyName[K comparable](policy EvictionPolicy[K]) string {
+	if s, ok := policy.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", policy)
+}
+
+// debugEnabled reports whether debug-level events are logged, so that
+// callers can skip building them otherwise.
+func (c *Cache[K, V]) debugEnabled() bool {
+	return c.logger != nil && c.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// debug logs a debug-level event, if debug logging is enabled.
+func (c *Cache[K, V]) debug(msg string, args ...any) {
+	if c.debugEnabled() {
+		c.logger.Debug(msg, args...)
+	}
+}
@@ -0,0 +1,108 @@
+package cache_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/policies"
+)
+
+// records decodes the JSON log records written to buf.
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var out []map[string]any
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var record map[string]any
+		require.NoError(t, decoder.Decode(&record))
+		out = append(out, record)
+	}
+	return out
+}
+
+func newTestLogger(buf *bytes.Buffer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: level}))
+}
+
+func TestCache_NoGlobalLogging(t *testing.T) {
+	var buf bytes.Buffer
+	original := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(original)
+
+	// Invalid arguments are corrected silently without a logger
+	c := cache.New[string, int](0, nil)
+	c.Resize(-1)
+	cache.NewSharded[string, int](0, 1, nil, nil)
+	require.Empty(t, buf.String())
+	require.Equal(t, 1, c.Capacity())
+}
+
+func TestCache_LogsWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	cache.New(0, policies.NewFIFO[string](),
+		cache.WithLogger[string, int](newTestLogger(&buf, slog.LevelInfo)),
+		cache.WithName[string, int]("users"),
+	)
+
+	logged := records(t, &buf)
+	require.Len(t, logged, 1)
+	require.Equal(t, "WARN", logged[0]["level"])
+	require.Equal(t, "users", logged[0]["cache"])
+	require.Equal(t, "fifo", logged[0]["policy"])
+	require.Equal(t, float64(0), logged[0]["capacity"])
+}
+
+func TestCache_LogsDebugEvents(t *testing.T) {
+	var buf bytes.Buffer
+	c, err := cache.NewWithOptions(
+		cache.WithCapacity[string, int](2),
+		cache.WithLogger[string, int](newTestLogger(&buf, slog.LevelDebug)),
+		cache.WithName[string, int]("users"),
+	)
+	require.NoError(t, err)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3) // Evicts "a"
+	c.Resize(1)   // Evicts "b"
+	c.GetOrLoad("d", func(string) (int, error) {
+		return 0, errors.New("unavailable")
+	})
+	c.Delete("c") // Deletions are not logged
+
+	logged := records(t, &buf)
+	var messages []string
+	for _, record := range logged {
+		messages = append(messages, record["msg"].(string))
+		require.Equal(t, "users", record["cache"])
+		require.Equal(t, "lru", record["policy"])
+	}
+	require.Equal(t, []string{
+		"cache eviction",
+		"cache resized",
+		"cache eviction",
+		"cache load failed",
+	}, messages)
+	require.Equal(t, "a", logged[0]["key"])
+	require.Equal(t, float64(1), logged[1]["evicted"])
+	require.Equal(t, "unavailable", logged[3]["error"])
+}
+
+func TestCache_DebugEventsNeedDebugLevel(t *testing.T) {
+	var buf bytes.Buffer
+	c := cache.New(1, nil, cache.WithLogger[string, int](newTestLogger(&buf, slog.LevelInfo)))
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Resize(5)
+	require.Empty(t, buf.String())
+}
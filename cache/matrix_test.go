@@ -0,0 +1,14 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/cachetest"
+)
+
+func BenchmarkCacheMatrix(b *testing.B) {
+	cachetest.RunMatrix(b, func(policy cache.EvictionPolicy[uint64], capacity int) cache.Cacheable[uint64, uint64] {
+		return cache.New[uint64, uint64](capacity, policy)
+	})
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Varun0157/in-mem-cache/clock"
+)
+
+// Errors returned by NewWithOptions for invalid configurations.
+var (
+	ErrInvalidCapacity    = errors.New("cache: capacity must be greater than 0")
+	ErrInvalidInitialSize = errors.New("cache: initial size must not be negative")
+)
+
+// Option configures a Cache created by NewWithOptions.
+type Option[K comparable, V any] func(*config[K, V])
+
+// config holds the settings collected from Options.
+type config[K comparable, V any] struct {
+	capacity    int
+	policy      EvictionPolicy[K]
+	weigher     func(key K, value V) int
+	listeners   []RemovalListener[K, V]
+	initialSize int
+	clock       clock.Clock
+	stats       *StatsCounter
+	logger      *slog.Logger
+	tracer      Tracer
+	name        string
+
+	initialSizeSet bool
+}
+
+// newConfig applies opts to an empty config.
+func newConfig[K comparable, V any](opts []Option[K, V]) config[K, V] {
+	var cfg config[K, V]
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithCapacity sets the maximum number of entries the cache holds or, if a
+// weigher is configured, the maximum total weight. It is required.
+func WithCapacity[K comparable, V any](capacity int) Option[K, V] {
+	return func(cfg *config[K, V]) {
+		cfg.capacity = capacity
+	}
+}
+
+// WithPolicy sets the eviction policy. It defaults to LRU.
+func WithPolicy[K comparable, V any](policy EvictionPolicy[K]) Option[K, V] {
+	return func(cfg *config[K, V]) {
+		cfg.policy = policy
+	}
+}
+
+// WithWeigher makes the capacity bound the total weight of the entries
+// rather than their number. weigher is called once per Set, with the cache
+// locked, and must return a non-negative weight; negative weights count as 0.
+// Entries are evicted until a new entry fits. An entry heavier than the
+// whole capacity is evicted as soon as it is set, replacing any previous
+// value for its key, so the total weight never exceeds the capacity.
+func WithWeigher[K comparable, V any](weigher func(key K, value V) int) Option[K, V] {
+	return func(cfg *config[K, V]) {
+		cfg.weigher = weigher
+	}
+}
+
+// WithRemovalListener registers a removal listener from the start, as
+// AddRemovalListener would. It may be given more than once.
+func WithRemovalListener[K comparable, V any](listener RemovalListener[K, V]) Option[K, V] {
+	return func(cfg *config[K, V]) {
+		if listener != nil {
+			cfg.listeners = append(cfg.listeners, listener)
+		}
+	}
+}
+
+// WithInitialSize sizes the cache's storage for n entries up front. It
+// defaults to the capacity when there is no weigher, and to 0 otherwise.
+func WithInitialSize[K comparable, V any](n int) Option[K, V] {
+	return func(cfg *config[K, V]) {
+		cfg.initialSize = n
+		cfg.initialSizeSet = true
+	}
+}
+
+// WithClock sets the source of time used to measure loads. It defaults to
+// the system clock; tests can pass a clocktest.Fake.
+func WithClock[K comparable, V any](clk clock.Clock) Option[K, V] {
+	return func(cfg *config[K, V]) {
+		cfg.clock = clk
+	}
+}
+
+// WithStats enables the statistics returned by Stats. They are off by
+// default, which saves a few atomic operations per call.
+func WithStats[K comparable, V any]() Option[K, V] {
+	return func(cfg *config[K, V]) {
+		cfg.stats = NewStatsCounter()
+	}
+}
+
+// WithLogger makes the cache log debug-level events, such as evictions,
+// resizes and loader failures, and warnings about invalid arguments, to
+// logger. Records carry a "policy" attribute, and a "cache" attribute if
+// the cache has a name. A nil logger, the default, turns logging off.
+func WithLogger[K comparable, V any](logger *slog.Logger) Option[K, V] {
+	return func(cfg *config[K, V]) {
+		cfg.logger = logger
+	}
+}
+
+// WithTracer makes the cache start a span through tracer around every Get,
+// Set, Delete and loader call. Tracing is off by default.
+func WithTracer[K comparable, V any](tracer Tracer) Option[K, V] {
+	return func(cfg *config[K, V]) {
+		cfg.tracer = tracer
+	}
+}
+
+// WithName names the cache in its log records and trace spans.
+func WithName[K comparable, V any](name string) Option[K, V] {
+	return func(cfg *config[K, V]) {
+		cfg.name = name
+	}
+}
+
+// NewWithOptions creates a new Cache from the given options, and returns an
+// error rather than guessing if they are invalid. WithCapacity is required.
+func NewWithOptions[K comparable, V any](opts ...Option[K, V]) (*Cache[K, V], error) {
+	cfg := newConfig(opts)
+
+	if cfg.capacity <= 0 {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidCapacity, cfg.capacity)
+	}
+	if cfg.initialSize < 0 {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidInitialSize, cfg.initialSize)
+	}
+	return newCache(cfg), nil
+}
@@ -0,0 +1,140 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/policies"
+)
+
+func TestNewWithOptions_Validation(t *testing.T) {
+	// Capacity is required
+	_, err := cache.NewWithOptions(
+		cache.WithPolicy[string, int](policies.NewLRU[string]()),
+	)
+	require.ErrorIs(t, err, cache.ErrInvalidCapacity)
+
+	_, err = cache.NewWithOptions(
+		cache.WithCapacity[string, int](-1),
+		cache.WithPolicy[string, int](policies.NewLRU[string]()),
+	)
+	require.ErrorIs(t, err, cache.ErrInvalidCapacity)
+
+	_, err = cache.NewWithOptions(
+		cache.WithCapacity[string, int](10),
+		cache.WithPolicy[string, int](policies.NewLRU[string]()),
+		cache.WithInitialSize[string, int](-5),
+	)
+	require.ErrorIs(t, err, cache.ErrInvalidInitialSize)
+}
+
+func TestNewWithOptions(t *testing.T) {
+	var evicted []string
+	c, err := cache.NewWithOptions(
+		cache.WithCapacity[string, int](2),
+		cache.WithPolicy[string, int](policies.NewFIFO[string]()),
+		cache.WithInitialSize[string, int](16),
+		cache.WithRemovalListener(func(key string, _ int, reason cache.RemovalReason) {
+			require.Equal(t, cache.Evicted, reason)
+			evicted = append(evicted, key)
+		}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, c.Capacity())
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3) // Evicts "a"
+
+	require.Equal(t, []string{"a"}, evicted)
+	require.Equal(t, 2, c.Len())
+	require.Equal(t, 2, c.Weight())
+}
+
+func TestCache_Weigher(t *testing.T) {
+	c, err := cache.NewWithOptions(
+		cache.WithCapacity[string, string](10),
+		cache.WithPolicy[string, string](policies.NewLRU[string]()),
+		cache.WithWeigher(func(_ string, value string) int {
+			return len(value)
+		}),
+	)
+	require.NoError(t, err)
+
+	c.Set("a", "1234")
+	c.Set("b", "1234")
+	require.Equal(t, 8, c.Weight())
+
+	// Touch "a" so that "b" is the least recently used
+	c.Get("a")
+
+	// Needs 3 more units than are free, so "b" is evicted
+	c.Set("c", "12345")
+	require.False(t, c.Contains("b"))
+	require.True(t, c.Contains("a"))
+	require.Equal(t, 9, c.Weight())
+
+	// Updating a value re-weighs it, evicting others if it grew
+	c.Set("c", "1234567")
+	require.False(t, c.Contains("a"))
+	require.Equal(t, 7, c.Weight())
+	require.Equal(t, 1, c.Len())
+
+	c.Delete("c")
+	require.Equal(t, 0, c.Weight())
+}
+
+func TestCache_WeigherOversizedEntry(t *testing.T) {
+	var evicted []string
+	c, err := cache.NewWithOptions(
+		cache.WithCapacity[string, string](4),
+		cache.WithPolicy[string, string](policies.NewFIFO[string]()),
+		cache.WithWeigher(func(_ string, value string) int {
+			return len(value)
+		}),
+		cache.WithRemovalListener(func(key string, value string, reason cache.RemovalReason) {
+			evicted = append(evicted, key+"="+value)
+		}),
+	)
+	require.NoError(t, err)
+
+	c.Set("a", "12")
+	c.Set("b", "12")
+
+	// An entry heavier than the capacity is evicted at once, leaving the
+	// others alone
+	c.Set("big", "123456")
+	require.ElementsMatch(t, []string{"a", "b"}, c.Keys())
+	require.Equal(t, 4, c.Weight())
+	require.Equal(t, []string{"big=123456"}, evicted)
+
+	// Growing an existing entry past the capacity drops the key too
+	c.Set("a", "123456")
+	require.Equal(t, []string{"b"}, c.Keys())
+	require.Equal(t, 2, c.Weight())
+	require.Equal(t, []string{"big=123456", "a=123456"}, evicted)
+	require.LessOrEqual(t, c.Weight(), c.Capacity())
+}
+
+func TestCache_WeigherResize(t *testing.T) {
+	c, err := cache.NewWithOptions(
+		cache.WithCapacity[int, []byte](100),
+		cache.WithPolicy[int, []byte](policies.NewFIFO[int]()),
+		cache.WithWeigher(func(_ int, value []byte) int {
+			return len(value)
+		}),
+	)
+	require.NoError(t, err)
+
+	for i := range 5 {
+		c.Set(i, make([]byte, 20))
+	}
+	require.Equal(t, 100, c.Weight())
+
+	// Shrinking evicts the oldest entries until the rest fit
+	c.Resize(50)
+	require.Equal(t, 40, c.Weight())
+	require.ElementsMatch(t, []int{3, 4}, c.Keys())
+}
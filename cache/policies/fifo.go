@@ -21,6 +21,11 @@ func NewFIFO[K comparable]() cache.EvictionPolicy[K] {
 	}
 }
 
+// String returns the name of the policy, as used by policies.Lookup.
+func (p *fifoPolicy[K]) String() string {
+	return "fifo"
+}
+
 func (p *fifoPolicy[K]) OnAdd(key K) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
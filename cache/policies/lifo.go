@@ -21,6 +21,11 @@ func NewLIFO[K comparable]() cache.EvictionPolicy[K] {
 	}
 }
 
+// String returns the name of the policy, as used by policies.Lookup.
+func (p *lifoPolicy[K]) String() string {
+	return "lifo"
+}
+
 func (p *lifoPolicy[K]) OnAdd(key K) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
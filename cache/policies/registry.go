@@ -0,0 +1,107 @@
+package policies
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+)
+
+// ErrUnknownPolicy is returned by Lookup for names that are neither built in
+// nor registered.
+var ErrUnknownPolicy = errors.New("policies: unknown policy")
+
+// Names of the built-in policies, as accepted by Lookup.
+const (
+	LRU  = "lru"
+	FIFO = "fifo"
+	LIFO = "lifo"
+)
+
+var (
+	registryMu sync.RWMutex
+	// registry maps a normalised name to a cache.PolicyFactory[K] for the
+	// key type it was registered with.
+	registry = make(map[string]any)
+)
+
+// normalize makes policy names case-insensitive and tolerant of surrounding
+// whitespace, as they usually come from configuration.
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Register makes a custom policy factory available to Lookup under name,
+// for caches keyed by K. Like database/sql.Register, it is meant to be
+// called from init functions, and it panics if factory is nil or if name is
+// empty, built in, or already registered.
+func Register[K comparable](name string, factory cache.PolicyFactory[K]) {
+	if factory == nil {
+		panic("policies: Register factory is nil")
+	}
+	key := normalize(name)
+	if key == "" {
+		panic("policies: Register name is empty")
+	}
+	if isBuiltin(key) {
+		panic("policies: Register called for built-in policy " + key)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, dup := registry[key]; dup {
+		panic("policies: Register called twice for policy " + key)
+	}
+	registry[key] = factory
+}
+
+// Lookup returns the factory for the named policy, for caches keyed by K.
+// Names are case-insensitive. The built-in "lru", "fifo" and "lifo" policies
+// work for any key type; custom policies only for the key type they were
+// registered with.
+func Lookup[K comparable](name string) (cache.PolicyFactory[K], error) {
+	key := normalize(name)
+	switch key {
+	case LRU:
+		return NewLRU[K], nil
+	case FIFO:
+		return NewFIFO[K], nil
+	case LIFO:
+		return NewLIFO[K], nil
+	}
+
+	registryMu.RLock()
+	registered, ok := registry[key]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w %q (known: %s)", ErrUnknownPolicy, name, strings.Join(Names(), ", "))
+	}
+	factory, ok := registered.(cache.PolicyFactory[K])
+	if !ok {
+		return nil, fmt.Errorf("policies: policy %q is registered as %T, not for keys of type %v", name, registered, reflect.TypeFor[K]())
+	}
+	return factory, nil
+}
+
+// Names returns the names of the built-in and registered policies, sorted.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := []string{LRU, FIFO, LIFO}
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func isBuiltin(name string) bool {
+	return name == LRU || name == FIFO || name == LIFO
+}
@@ -0,0 +1,70 @@
+package policies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+)
+
+func TestLookup_Builtins(t *testing.T) {
+	// Names are case-insensitive and may carry whitespace from config
+	for _, name := range []string{"lru", "FIFO", " Lifo "} {
+		factory, err := Lookup[string](name)
+		require.NoError(t, err, name)
+		require.NotNil(t, factory())
+	}
+
+	// Each call of a factory creates an independent policy
+	factory, err := Lookup[int](LIFO)
+	require.NoError(t, err)
+	p1, p2 := factory(), factory()
+	p1.OnAdd(1)
+	p2.OnAdd(2)
+	require.Equal(t, 1, p1.OnEvict())
+	require.Equal(t, 2, p2.OnEvict())
+}
+
+func TestLookup_Unknown(t *testing.T) {
+	_, err := Lookup[string]("clock")
+	require.ErrorIs(t, err, ErrUnknownPolicy)
+	require.ErrorContains(t, err, "fifo, lifo, lru")
+}
+
+// unregister removes a policy registered by a test, so that the test can run
+// again in the same process, e.g. with -count.
+func unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(registry, normalize(name))
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-newest", NewLIFO[string])
+	t.Cleanup(func() { unregister("test-newest") })
+
+	factory, err := Lookup[string]("Test-Newest")
+	require.NoError(t, err)
+
+	c := cache.New[string, int](1, factory())
+	c.Set("a", 1)
+	c.Set("b", 2)
+	require.Equal(t, []string{"b"}, c.Keys())
+	require.Contains(t, Names(), "test-newest")
+
+	// Custom policies are tied to the key type they were registered with
+	_, err = Lookup[int]("test-newest")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrUnknownPolicy)
+
+	require.Panics(t, func() {
+		Register("test-newest", NewLRU[string])
+	})
+	require.Panics(t, func() {
+		Register("LRU", NewLRU[string])
+	})
+	require.Panics(t, func() {
+		Register[string]("test-nil", nil)
+	})
+}
@@ -16,3 +16,7 @@ type EvictionPolicy[K comparable] interface {
 	// It should return the key to be removed.
 	OnEvict() K
 }
+
+// PolicyFactory creates a fresh EvictionPolicy. It is used wherever a cache
+// needs more than one policy instance, e.g. one per shard.
+type PolicyFactory[K comparable] func() EvictionPolicy[K]
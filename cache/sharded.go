@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"hash/maphash"
+
+	"github.com/Varun0157/in-mem-cache/internal/lru"
+)
+
+// Sharded is a thread-safe cache that spreads keys across several independent
+// Cache instances, each with its own lock and eviction policy, to reduce lock
+// contention under high concurrency. Eviction decisions are made per shard.
+type Sharded[K comparable, V any] struct {
+	shards []*Cache[K, V]
+	hasher func(K) uint64
+}
+
+// NewSharded creates a new Sharded cache with the given number of shards,
+// each holding up to capacityPerShard entries and using a policy created by
+// newPolicy, or LRU if newPolicy is nil. Keys are assigned to shards by
+// hasher; if hasher is nil, keys are hashed with maphash.Comparable and a
+// random seed. opts are applied to every shard, as for New, except WithPolicy:
+// a policy tracks the keys of a single cache, so it cannot be shared.
+func NewSharded[K comparable, V any](shards, capacityPerShard int, newPolicy PolicyFactory[K], hasher func(K) uint64, opts ...Option[K, V]) *Sharded[K, V] {
+	if shards <= 0 {
+		if logger := newConfig(opts).logger; logger != nil {
+			logger.Warn("shard count must be greater than 0, defaulting to 1", "shards", shards)
+		}
+		shards = 1
+	}
+	if hasher == nil {
+		seed := maphash.MakeSeed()
+		hasher = func(key K) uint64 {
+			return maphash.Comparable(seed, key)
+		}
+	}
+
+	s := &Sharded[K, V]{
+		shards: make([]*Cache[K, V], shards),
+		hasher: hasher,
+	}
+	for i := range s.shards {
+		// Always pass a policy, so that each shard gets its own even if
+		// opts include WithPolicy.
+		var policy EvictionPolicy[K] = lru.New[K]()
+		if newPolicy != nil {
+			policy = newPolicy()
+		}
+		s.shards[i] = New(capacityPerShard, policy, opts...)
+	}
+	return s
+}
+
+// shard returns the shard responsible for key.
+func (s *Sharded[K, V]) shard(key K) *Cache[K, V] {
+	return s.shards[s.hasher(key)%uint64(len(s.shards))]
+}
+
+// Set adds or updates a value in the shard that owns the key.
+func (s *Sharded[K, V]) Set(key K, value V) {
+	s.shard(key).Set(key, value)
+}
+
+// Get retrieves a value from the shard that owns the key.
+func (s *Sharded[K, V]) Get(key K) (V, bool) {
+	return s.shard(key).Get(key)
+}
+
+// Peek retrieves a value from the shard that owns the key, without recording
+// an access with its eviction policy.
+func (s *Sharded[K, V]) Peek(key K) (V, bool) {
+	return s.shard(key).Peek(key)
+}
+
+// Contains reports whether the shard that owns the key holds it, without
+// recording an access with its eviction policy.
+func (s *Sharded[K, V]) Contains(key K) bool {
+	return s.shard(key).Contains(key)
+}
+
+// Delete removes a value from the shard that owns the key.
+func (s *Sharded[K, V]) Delete(key K) {
+	s.shard(key).Delete(key)
+}
+
+// AddRemovalListener registers a listener with every shard.
+func (s *Sharded[K, V]) AddRemovalListener(listener RemovalListener[K, V]) {
+	for _, shard := range s.shards {
+		shard.AddRemovalListener(listener)
+	}
+}
+
+// Static assertions to ensure *Sharded satisfies the Cacheable and Peeker
+// interfaces.
+var (
+	_ Cacheable[any, any] = (*Sharded[any, any])(nil)
+	_ Peeker[any, any]    = (*Sharded[any, any])(nil)
+)
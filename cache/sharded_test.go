@@ -0,0 +1,169 @@
+package cache_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/policies"
+)
+
+func TestSharded_Basic(t *testing.T) {
+	c := cache.NewSharded[string, int](4, 10, policies.NewLRU[string], nil)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	val, found := c.Get("a")
+	require.True(t, found)
+	require.Equal(t, 1, val)
+
+	c.Delete("a")
+	_, found = c.Get("a")
+	require.False(t, found)
+
+	val, found = c.Get("b")
+	require.True(t, found)
+	require.Equal(t, 2, val)
+}
+
+func TestSharded_EvictsPerShard(t *testing.T) {
+	// Send even keys to shard 0 and odd keys to shard 1
+	hasher := func(key int) uint64 { return uint64(key) }
+	c := cache.NewSharded[int, int](2, 2, policies.NewFIFO[int], hasher)
+
+	c.Set(0, 0)
+	c.Set(2, 2)
+	c.Set(1, 1)
+	c.Set(4, 4) // Evicts 0, the oldest key in the even shard
+
+	_, found := c.Get(0)
+	require.False(t, found)
+	for _, key := range []int{1, 2, 4} {
+		_, found := c.Get(key)
+		require.True(t, found, "key %d should still be cached", key)
+	}
+}
+
+func TestSharded_IgnoresSharedPolicyOption(t *testing.T) {
+	// A policy given through WithPolicy would be shared by every shard
+	c := cache.NewSharded[int, int](4, 2, nil, nil, cache.WithPolicy[int, int](policies.NewFIFO[int]()))
+	for i := range 100 {
+		c.Set(i, i)
+	}
+
+	cached := 0
+	for i := range 100 {
+		if _, found := c.Get(i); found {
+			cached++
+		}
+	}
+	require.LessOrEqual(t, cached, 8)
+}
+
+func TestSharded_PeekAndContains(t *testing.T) {
+	c := cache.NewSharded[int, int](2, 2, policies.NewLRU[int], func(key int) uint64 { return 0 })
+	c.Set(1, 1)
+	c.Set(2, 2)
+
+	// Neither counts as an access, so 1 is still the least recently used
+	val, found := c.Peek(1)
+	require.True(t, found)
+	require.Equal(t, 1, val)
+	require.True(t, c.Contains(1))
+
+	c.Set(3, 3)
+	require.False(t, c.Contains(1))
+	_, found = c.Peek(1)
+	require.False(t, found)
+}
+
+func TestSharded_StructKeys(t *testing.T) {
+	type point struct{ X, Y int }
+	c := cache.NewSharded[point, string](8, 10, policies.NewLRU[point], nil)
+
+	c.Set(point{1, 2}, "a")
+	val, found := c.Get(point{1, 2})
+	require.True(t, found)
+	require.Equal(t, "a", val)
+}
+
+func TestSharded_ZeroShards(t *testing.T) {
+	// Should default to a single shard
+	c := cache.NewSharded[string, int](0, 1, policies.NewLRU[string], nil)
+
+	c.Set("a", 1)
+	c.Set("b", 2) // Should evict "a"
+
+	_, found := c.Get("a")
+	require.False(t, found)
+}
+
+func TestSharded_RemovalListener(t *testing.T) {
+	c := cache.NewSharded[int, int](4, 1, policies.NewLRU[int], func(key int) uint64 { return uint64(key) })
+
+	var evicted []int
+	c.AddRemovalListener(func(key int, _ int, reason cache.RemovalReason) {
+		if reason == cache.Evicted {
+			evicted = append(evicted, key)
+		}
+	})
+
+	c.Set(1, 1)
+	c.Set(5, 5) // Same shard as 1, evicts it
+	c.Set(2, 2) // Different shard, nothing evicted
+
+	require.Equal(t, []int{1}, evicted)
+}
+
+func TestSharded_ConcurrentAccess(t *testing.T) {
+	c := cache.NewSharded[int, int](16, 100, policies.NewLRU[int], nil)
+	var wg sync.WaitGroup
+	numGoroutines := 10
+	numOperations := 1000
+
+	for i := range numGoroutines {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for j := range numOperations {
+				key := id*numOperations + j
+				c.Set(key, key)
+				val, found := c.Get(key)
+				if found {
+					require.Equal(t, key, val)
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkShardedSetParallel(b *testing.B) {
+	c := cache.NewSharded[int, int](64, 1024, policies.NewLRU[int], nil)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			c.Set(i, i)
+			i++
+		}
+	})
+}
+
+func BenchmarkCacheSetParallel(b *testing.B) {
+	c := cache.New[int, int](64*1024, policies.NewLRU[int]())
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			c.Set(i, i)
+			i++
+		}
+	})
+}
@@ -0,0 +1,252 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a point-in-time snapshot of the statistics of a cache. Counters
+// only ever grow; use Sub to get the activity over an interval.
+type Stats struct {
+	Hits      uint64 // Lookups that found a value
+	Misses    uint64 // Lookups that did not
+	Sets      uint64 // Writes that added an entry
+	Updates   uint64 // Writes that replaced the value of an existing entry
+	Deletes   uint64 // Entries removed explicitly
+	Evictions uint64 // Entries removed by the eviction policy to make room
+	// Entries removed because their time ran out. Only caches with
+	// expiration, like ttl.Cache, record these.
+	Expirations uint64
+
+	LoadSuccesses uint64        // Loader calls that returned a value
+	LoadFailures  uint64        // Loader calls that returned an error
+	LoadTime      time.Duration // Total time spent in loader calls
+	// LoadTimeCounts is a histogram of loader call durations. Element i
+	// counts the calls that took at most LoadTimeBounds()[i] but longer than
+	// the bound before it; the last element counts the calls slower than
+	// every bound.
+	LoadTimeCounts [numLoadTimeBuckets]uint64
+}
+
+// loadTimeBounds are the upper bounds of the load time histogram buckets.
+var loadTimeBounds = [...]time.Duration{
+	100 * time.Microsecond,
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// numLoadTimeBuckets includes the bucket for loads slower than every bound.
+const numLoadTimeBuckets = len(loadTimeBounds) + 1
+
+// LoadTimeBounds returns the upper bounds of the buckets of
+// Stats.LoadTimeCounts, in increasing order.
+func LoadTimeBounds() []time.Duration {
+	return loadTimeBounds[:]
+}
+
+// loadTimeBucket returns the index of the histogram bucket for a load that
+// took d.
+func loadTimeBucket(d time.Duration) int {
+	for i, bound := range loadTimeBounds {
+		if d <= bound {
+			return i
+		}
+	}
+	return len(loadTimeBounds)
+}
+
+// Requests returns the number of lookups, hits and misses alike.
+func (s Stats) Requests() uint64 {
+	return s.Hits + s.Misses
+}
+
+// HitRatio returns the fraction of lookups that were hits, or 0 if there
+// were none.
+func (s Stats) HitRatio() float64 {
+	requests := s.Requests()
+	if requests == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(requests)
+}
+
+// Loads returns the number of loader calls, successful or not.
+func (s Stats) Loads() uint64 {
+	return s.LoadSuccesses + s.LoadFailures
+}
+
+// AverageLoadTime returns the mean time spent in a loader call, or 0 if
+// there were none.
+func (s Stats) AverageLoadTime() time.Duration {
+	loads := s.Loads()
+	if loads == 0 {
+		return 0
+	}
+	return s.LoadTime / time.Duration(loads)
+}
+
+// Sub returns the statistics recorded since an earlier snapshot prev, e.g.
+// to report per-interval rates:
+//
+//	now := c.Stats()
+//	delta := now.Sub(last)
+//	last = now
+func (s Stats) Sub(prev Stats) Stats {
+	var counts [numLoadTimeBuckets]uint64
+	for i := range counts {
+		counts[i] = s.LoadTimeCounts[i] - prev.LoadTimeCounts[i]
+	}
+	return Stats{
+		Hits:           s.Hits - prev.Hits,
+		Misses:         s.Misses - prev.Misses,
+		Sets:           s.Sets - prev.Sets,
+		Updates:        s.Updates - prev.Updates,
+		Deletes:        s.Deletes - prev.Deletes,
+		Evictions:      s.Evictions - prev.Evictions,
+		Expirations:    s.Expirations - prev.Expirations,
+		LoadSuccesses:  s.LoadSuccesses - prev.LoadSuccesses,
+		LoadFailures:   s.LoadFailures - prev.LoadFailures,
+		LoadTime:       s.LoadTime - prev.LoadTime,
+		LoadTimeCounts: counts,
+	}
+}
+
+// Stats returns a snapshot of the statistics recorded since the cache was
+// created. It is all zeroes unless the cache was created WithStats.
+func (c *Cache[K, V]) Stats() Stats {
+	return c.stats.Snapshot()
+}
+
+// StatsCounter accumulates Stats with atomic counters, so recording never
+// takes a lock. The zero value is ready to use, and the methods of a nil
+// *StatsCounter do nothing, so callers can record unconditionally and leave
+// the counter nil to disable stats.
+type StatsCounter struct {
+	hits          paddedCounter
+	misses        paddedCounter
+	sets          atomic.Uint64
+	updates       atomic.Uint64
+	deletes       atomic.Uint64
+	evictions     atomic.Uint64
+	expirations   atomic.Uint64
+	loadSuccesses atomic.Uint64
+	loadFailures  atomic.Uint64
+	loadTime      atomic.Int64
+	loadBuckets   [numLoadTimeBuckets]atomic.Uint64
+}
+
+// paddedCounter keeps the hottest counters on cache lines of their own, so
+// that concurrent readers do not contend on them with writers.
+type paddedCounter struct {
+	atomic.Uint64
+	_ [56]byte
+}
+
+// NewStatsCounter creates an empty StatsCounter.
+func NewStatsCounter() *StatsCounter {
+	return &StatsCounter{}
+}
+
+// RecordHits records n lookups that found a value.
+func (s *StatsCounter) RecordHits(n int) {
+	if s != nil && n > 0 {
+		s.hits.Add(uint64(n))
+	}
+}
+
+// RecordMisses records n lookups that did not find a value.
+func (s *StatsCounter) RecordMisses(n int) {
+	if s != nil && n > 0 {
+		s.misses.Add(uint64(n))
+	}
+}
+
+// RecordSet records a write that added an entry.
+func (s *StatsCounter) RecordSet() {
+	if s != nil {
+		s.sets.Add(1)
+	}
+}
+
+// RecordUpdate records a write that replaced an existing value.
+func (s *StatsCounter) RecordUpdate() {
+	if s != nil {
+		s.updates.Add(1)
+	}
+}
+
+// RecordRemoval records an entry leaving the cache for the given reason.
+// Cleared entries are not counted.
+func (s *StatsCounter) RecordRemoval(reason RemovalReason) {
+	if s == nil {
+		return
+	}
+	switch reason {
+	case Deleted:
+		s.deletes.Add(1)
+	case Evicted:
+		s.evictions.Add(1)
+	case Expired:
+		s.expirations.Add(1)
+	}
+}
+
+// RecordLoadSuccess records a loader call that returned a value after d.
+func (s *StatsCounter) RecordLoadSuccess(d time.Duration) {
+	if s != nil {
+		s.loadSuccesses.Add(1)
+		s.recordLoadTime(d)
+	}
+}
+
+// RecordLoadFailure records a loader call that returned an error after d.
+func (s *StatsCounter) RecordLoadFailure(d time.Duration) {
+	if s != nil {
+		s.loadFailures.Add(1)
+		s.recordLoadTime(d)
+	}
+}
+
+func (s *StatsCounter) recordLoadTime(d time.Duration) {
+	s.loadTime.Add(int64(d))
+	s.loadBuckets[loadTimeBucket(d)].Add(1)
+}
+
+// Snapshot returns the statistics recorded so far. The counters are read one
+// by one, so a snapshot taken under load may be slightly inconsistent, e.g.
+// count a hit but not the load that preceded it.
+func (s *StatsCounter) Snapshot() Stats {
+	if s == nil {
+		return Stats{}
+	}
+	var counts [numLoadTimeBuckets]uint64
+	for i := range counts {
+		counts[i] = s.loadBuckets[i].Load()
+	}
+	return Stats{
+		Hits:           s.hits.Load(),
+		Misses:         s.misses.Load(),
+		Sets:           s.sets.Load(),
+		Updates:        s.updates.Load(),
+		Deletes:        s.deletes.Load(),
+		Evictions:      s.evictions.Load(),
+		Expirations:    s.expirations.Load(),
+		LoadSuccesses:  s.loadSuccesses.Load(),
+		LoadFailures:   s.loadFailures.Load(),
+		LoadTime:       time.Duration(s.loadTime.Load()),
+		LoadTimeCounts: counts,
+	}
+}
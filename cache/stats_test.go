@@ -0,0 +1,159 @@
+package cache_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/policies"
+	"github.com/Varun0157/in-mem-cache/clock/clocktest"
+)
+
+func TestCache_Stats(t *testing.T) {
+	c, err := cache.NewWithOptions(
+		cache.WithCapacity[string, int](2),
+		cache.WithPolicy[string, int](policies.NewFIFO[string]()),
+		cache.WithStats[string, int](),
+	)
+	require.NoError(t, err)
+
+	c.Set("a", 1)
+	c.Set("a", 2) // An update
+	c.Set("b", 3)
+	c.Set("c", 4) // Evicts "a"
+
+	c.Get("b")
+	c.Get("a")
+	c.GetMany([]string{"b", "c", "d"})
+	c.Delete("b")
+	c.Delete("b") // Not there any more, so not counted
+	c.Clear()     // Cleared entries are not deletions
+
+	stats := c.Stats()
+	require.Equal(t, cache.Stats{
+		Hits:      3,
+		Misses:    2,
+		Sets:      3,
+		Updates:   1,
+		Deletes:   1,
+		Evictions: 1,
+	}, stats)
+	require.Equal(t, uint64(5), stats.Requests())
+	require.InDelta(t, 0.6, stats.HitRatio(), 1e-9)
+}
+
+func TestCache_StatsGetManyDuplicates(t *testing.T) {
+	c := cache.New[string, int](10, nil, cache.WithStats[string, int]())
+	c.Set("a", 1)
+
+	// Each key asked for counts, as with Get
+	c.GetMany([]string{"a", "a", "a", "x", "x"})
+	stats := c.Stats()
+	require.Equal(t, uint64(3), stats.Hits)
+	require.Equal(t, uint64(2), stats.Misses)
+}
+
+func TestCache_StatsDisabled(t *testing.T) {
+	c := cache.New[string, int](2, nil)
+	c.Set("a", 1)
+	c.Get("a")
+	c.Get("b")
+
+	require.Equal(t, cache.Stats{}, c.Stats())
+	require.Zero(t, c.Stats().HitRatio())
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	clk := clocktest.NewFake(time.Unix(0, 0))
+	c, err := cache.NewWithOptions(
+		cache.WithCapacity[string, int](10),
+		cache.WithClock[string, int](clk),
+		cache.WithStats[string, int](),
+	)
+	require.NoError(t, err)
+
+	calls := 0
+	loader := func(key string) (int, error) {
+		calls++
+		clk.Advance(30 * time.Millisecond)
+		if key == "bad" {
+			return 0, errors.New("not found")
+		}
+		return len(key), nil
+	}
+
+	value, err := c.GetOrLoad("abc", loader)
+	require.NoError(t, err)
+	require.Equal(t, 3, value)
+
+	// The loaded value is cached
+	value, err = c.GetOrLoad("abc", loader)
+	require.NoError(t, err)
+	require.Equal(t, 3, value)
+	require.Equal(t, 1, calls)
+
+	// Failures are returned and nothing is stored
+	_, err = c.GetOrLoad("bad", loader)
+	require.EqualError(t, err, "not found")
+	require.False(t, c.Contains("bad"))
+
+	stats := c.Stats()
+	require.Equal(t, uint64(1), stats.LoadSuccesses)
+	require.Equal(t, uint64(1), stats.LoadFailures)
+	require.Equal(t, 60*time.Millisecond, stats.LoadTime)
+	require.Equal(t, 30*time.Millisecond, stats.AverageLoadTime())
+	require.Equal(t, uint64(1), stats.Hits)
+	require.Equal(t, uint64(2), stats.Misses)
+}
+
+func TestStats_Sub(t *testing.T) {
+	counter := cache.NewStatsCounter()
+	counter.RecordHits(3)
+	counter.RecordMisses(1)
+	counter.RecordLoadFailure(time.Second)
+	before := counter.Snapshot()
+
+	counter.RecordHits(1)
+	counter.RecordMisses(3)
+	counter.RecordRemoval(cache.Expired)
+	counter.RecordLoadSuccess(time.Second)
+
+	// The delta only covers what happened since the earlier snapshot
+	delta := counter.Snapshot().Sub(before)
+	expected := cache.Stats{
+		Hits:          1,
+		Misses:        3,
+		Expirations:   1,
+		LoadSuccesses: 1,
+		LoadTime:      time.Second,
+	}
+	expected.LoadTimeCounts[slices.Index(cache.LoadTimeBounds(), time.Second)] = 1
+	require.Equal(t, expected, delta)
+	require.InDelta(t, 0.25, delta.HitRatio(), 1e-9)
+
+	// A nil counter records nothing, without panicking
+	var disabled *cache.StatsCounter
+	disabled.RecordHits(1)
+	require.Equal(t, cache.Stats{}, disabled.Snapshot())
+}
+
+func TestStats_LoadTimeHistogram(t *testing.T) {
+	counter := cache.NewStatsCounter()
+	bounds := cache.LoadTimeBounds()
+	require.True(t, slices.IsSorted(bounds))
+
+	counter.RecordLoadSuccess(0)
+	counter.RecordLoadSuccess(bounds[0]) // Bounds are inclusive
+	counter.RecordLoadSuccess(bounds[0] + 1)
+	counter.RecordLoadFailure(time.Hour) // Slower than every bound
+
+	counts := counter.Snapshot().LoadTimeCounts
+	require.Len(t, counts, len(bounds)+1)
+	require.Equal(t, uint64(2), counts[0])
+	require.Equal(t, uint64(1), counts[1])
+	require.Equal(t, uint64(1), counts[len(bounds)])
+}
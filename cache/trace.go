@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"hash/maphash"
+	"log/slog"
+)
+
+// Tracer starts spans around cache operations. It lets a cache report to a
+// tracing system, such as OpenTelemetry, through a small adapter, without
+// this module depending on one. Start is called on every traced operation,
+// so it should be cheap.
+type Tracer interface {
+	// Start begins a span with the given name and initial attributes.
+	Start(name string, attrs ...slog.Attr) Span
+}
+
+// Span is a single traced operation, started by a Tracer.
+type Span interface {
+	// SetAttributes adds attributes describing the outcome of the operation.
+	SetAttributes(attrs ...slog.Attr)
+	// RecordError records that the operation failed with err.
+	RecordError(err error)
+	// End finishes the span. It is called exactly once.
+	End()
+}
+
+// Names of the spans started by the caches in this module.
+const (
+	SpanGet    = "cache.get"
+	SpanSet    = "cache.set"
+	SpanDelete = "cache.delete"
+	SpanLoad   = "cache.load" // The loader call of GetOrLoad
+)
+
+// Keys of the span attributes set by the caches in this module.
+const (
+	// AttrKeyHash is the hash of the key the operation is for, as returned
+	// by KeyHash. Keys themselves are not recorded, as they may be sensitive.
+	AttrKeyHash = "cache.key_hash"
+	// AttrName is the name of the cache, if it has one.
+	AttrName = "cache.name"
+	// AttrHit reports whether a Get found a value, or a Delete a key.
+	AttrHit = "cache.hit"
+	// AttrEvicted is the number of entries a Set evicted.
+	AttrEvicted = "cache.evicted"
+	// AttrExpired reports whether a Get found an entry that had expired.
+	AttrExpired = "cache.expired"
+)
+
+// keyHashSeed is shared by every cache in the process, so that spans for the
+// same key correlate across caches and decorators.
+var keyHashSeed = maphash.MakeSeed()
+
+// KeyHash returns the hash recorded for key as AttrKeyHash. It is stable
+// within a process, but not across processes.
+func KeyHash[K comparable](key K) uint64 {
+	return maphash.Comparable(keyHashSeed, key)
+}
+
+// startSpan starts a span for an operation on key, or returns nil if the
+// cache has no tracer.
+func (c *Cache[K, V]) startSpan(name string, key K) Span {
+	if c.tracer == nil {
+		return nil
+	}
+	if c.name != "" {
+		return c.tracer.Start(name, slog.Uint64(AttrKeyHash, KeyHash(key)), slog.String(AttrName, c.name))
+	}
+	return c.tracer.Start(name, slog.Uint64(AttrKeyHash, KeyHash(key)))
+}
@@ -0,0 +1,59 @@
+package cache_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/cachetest"
+)
+
+func TestCache_Tracer(t *testing.T) {
+	tracer := cachetest.NewTracer()
+	c := cache.New(1, nil,
+		cache.WithTracer[string, int](tracer),
+		cache.WithName[string, int]("users"),
+	)
+
+	c.Set("a", 1)
+	c.Set("b", 2) // Evicts "a"
+	c.Get("a")
+	c.Get("b")
+	c.Delete("b")
+	_, err := c.GetOrLoad("c", func(string) (int, error) {
+		return 0, errors.New("unavailable")
+	})
+	require.Error(t, err)
+
+	spans := tracer.Spans()
+	var names []string
+	for _, span := range spans {
+		names = append(names, span.Name)
+		require.True(t, span.Ended, span.Name)
+		require.Equal(t, "users", span.Attrs[cache.AttrName].String())
+	}
+	require.Equal(t, []string{
+		cache.SpanSet,
+		cache.SpanSet,
+		cache.SpanGet,
+		cache.SpanGet,
+		cache.SpanDelete,
+		cache.SpanGet, // The lookup made by GetOrLoad
+		cache.SpanLoad,
+	}, names)
+
+	require.Equal(t, int64(0), spans[0].Attrs[cache.AttrEvicted].Int64())
+	require.Equal(t, int64(1), spans[1].Attrs[cache.AttrEvicted].Int64())
+	require.False(t, spans[2].Attrs[cache.AttrHit].Bool())
+	require.True(t, spans[3].Attrs[cache.AttrHit].Bool())
+	require.True(t, spans[4].Attrs[cache.AttrHit].Bool())
+	require.EqualError(t, spans[6].Err, "unavailable")
+
+	// Keys are identified by their hash only
+	require.Equal(t, slog.Uint64Value(cache.KeyHash("a")), spans[0].Attrs[cache.AttrKeyHash])
+	require.Equal(t, spans[0].Attrs[cache.AttrKeyHash], spans[2].Attrs[cache.AttrKeyHash])
+	require.NotEqual(t, spans[0].Attrs[cache.AttrKeyHash], spans[1].Attrs[cache.AttrKeyHash])
+}
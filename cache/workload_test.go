@@ -0,0 +1,44 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/policies"
+	"github.com/Varun0157/in-mem-cache/workload"
+)
+
+// BenchmarkPolicyWorkloads replays each standard workload through every
+// registered policy, reading each key and storing it on a miss. Besides the
+// time per access, it reports the hit ratio, which is what the policy is for.
+func BenchmarkPolicyWorkloads(b *testing.B) {
+	const (
+		keys     = 100_000
+		capacity = keys / 10
+		traceLen = 1 << 18 // A power of two, to index with a mask
+	)
+
+	for _, w := range workload.Standard(keys) {
+		trace := workload.Keys(w.New(1), traceLen)
+		for _, name := range policies.Names() {
+			newPolicy, err := policies.Lookup[uint64](name)
+			require.NoError(b, err)
+
+			b.Run(w.Name+"/"+name, func(b *testing.B) {
+				c := cache.New(capacity, newPolicy(), cache.WithStats[uint64, uint64]())
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					key := trace[i&(traceLen-1)]
+					if _, ok := c.Get(key); !ok {
+						c.Set(key, key)
+					}
+				}
+				b.ReportMetric(c.Stats().HitRatio(), "hit-ratio")
+			})
+		}
+	}
+}
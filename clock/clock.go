@@ -0,0 +1,74 @@
+// Package clock abstracts the passage of time so that time-based components
+// of this module can be driven deterministically in tests.
+package clock
+
+import "time"
+
+// Clock is the source of time used by time-based components.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer creates a Timer that sends the current time on its channel
+	// after at least duration d.
+	NewTimer(d time.Duration) Timer
+
+	// AfterFunc waits for duration d to elapse and then calls f in its own
+	// goroutine. The returned Timer can be used to cancel the call.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is the subset of *time.Timer used by this module.
+type Timer interface {
+	// C returns the channel on which the time is delivered. It is nil for
+	// timers created by AfterFunc.
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing. It reports whether the call
+	// stopped the timer.
+	Stop() bool
+
+	// Reset changes the timer to expire after duration d. It reports whether
+	// the timer had been active.
+	Reset(d time.Duration) bool
+}
+
+// realClock is a Clock backed by the time package.
+type realClock struct{}
+
+// Real returns a Clock backed by the system clock.
+func Real() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{time.AfterFunc(d, f)}
+}
+
+// realTimer adapts *time.Timer to the Timer interface.
+type realTimer struct {
+	t *time.Timer
+}
+
+func (r realTimer) C() <-chan time.Time {
+	return r.t.C
+}
+
+func (r realTimer) Stop() bool {
+	return r.t.Stop()
+}
+
+func (r realTimer) Reset(d time.Duration) bool {
+	return r.t.Reset(d)
+}
+
+// Static assertion to ensure realClock satisfies the Clock interface.
+var _ Clock = realClock{}
@@ -0,0 +1,146 @@
+// Package clocktest provides a manually driven clock.Clock for tests.
+package clocktest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Varun0157/in-mem-cache/clock"
+)
+
+// Fake is a clock.Clock whose time only moves when the test says so.
+// Timers and AfterFunc callbacks fire synchronously from Advance and Set,
+// in deadline order.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeTimer
+}
+
+// NewFake creates a Fake clock that reads start until it is advanced.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now returns the fake current time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// NewTimer creates a timer that fires once the fake time reaches now+d.
+func (f *Fake) NewTimer(d time.Duration) clock.Timer {
+	t := &fakeTimer{clock: f, ch: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// AfterFunc arranges for fn to be called once the fake time reaches now+d.
+func (f *Fake) AfterFunc(d time.Duration, fn func()) clock.Timer {
+	t := &fakeTimer{clock: f, fn: fn}
+	t.Reset(d)
+	return t
+}
+
+// Advance moves the fake time forward by d, firing every timer that comes due.
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the fake time to t, firing every timer that comes due. Time
+// never moves backwards; an earlier t is ignored.
+func (f *Fake) Set(t time.Time) {
+	for {
+		f.mu.Lock()
+		if len(f.waiters) == 0 || f.waiters[0].when.After(t) {
+			if t.After(f.now) {
+				f.now = t
+			}
+			f.mu.Unlock()
+			return
+		}
+
+		// Step to the next deadline so callbacks observe the time they were due.
+		next := f.waiters[0]
+		f.waiters = f.waiters[1:]
+		if next.when.After(f.now) {
+			f.now = next.when
+		}
+		now := f.now
+		f.mu.Unlock()
+
+		next.fire(now)
+	}
+}
+
+// Waiters returns the number of timers that have not fired or been stopped.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.waiters)
+}
+
+// remove unschedules t. It reports whether t was pending. f.mu must be held.
+func (f *Fake) remove(t *fakeTimer) bool {
+	for i, w := range f.waiters {
+		if w == t {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// fakeTimer is a clock.Timer driven by a Fake clock.
+type fakeTimer struct {
+	clock *Fake
+	when  time.Time
+	ch    chan time.Time
+	fn    func()
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	f := t.clock
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	active := f.remove(t)
+	t.when = f.now.Add(d)
+	i := sort.Search(len(f.waiters), func(i int) bool {
+		return f.waiters[i].when.After(t.when)
+	})
+	f.waiters = append(f.waiters, nil)
+	copy(f.waiters[i+1:], f.waiters[i:])
+	f.waiters[i] = t
+	return active
+}
+
+// fire delivers the time on the timer's channel or runs its callback.
+func (t *fakeTimer) fire(now time.Time) {
+	if t.fn != nil {
+		t.fn()
+		return
+	}
+	select {
+	case t.ch <- now:
+	default:
+	}
+}
+
+// Static assertion to ensure *Fake satisfies the clock.Clock interface.
+var _ clock.Clock = (*Fake)(nil)
@@ -0,0 +1,78 @@
+package clocktest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFake_Now(t *testing.T) {
+	start := time.Unix(100, 0)
+	clk := NewFake(start)
+	require.Equal(t, start, clk.Now())
+
+	clk.Advance(time.Second)
+	require.Equal(t, start.Add(time.Second), clk.Now())
+
+	// Time never moves backwards
+	clk.Set(start)
+	require.Equal(t, start.Add(time.Second), clk.Now())
+}
+
+func TestFake_Timer(t *testing.T) {
+	start := time.Unix(100, 0)
+	clk := NewFake(start)
+	timer := clk.NewTimer(time.Minute)
+
+	clk.Advance(30 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired early")
+	default:
+	}
+
+	clk.Advance(time.Hour)
+	select {
+	case fired := <-timer.C():
+		// The timer reports the time it was due, not the time advanced to
+		require.Equal(t, start.Add(time.Minute), fired)
+	default:
+		t.Fatal("timer did not fire")
+	}
+	require.Equal(t, 0, clk.Waiters())
+}
+
+func TestFake_AfterFunc(t *testing.T) {
+	clk := NewFake(time.Unix(100, 0))
+	var calls []string
+
+	clk.AfterFunc(2*time.Second, func() { calls = append(calls, "second") })
+	clk.AfterFunc(time.Second, func() { calls = append(calls, "first") })
+	stopped := clk.AfterFunc(time.Second, func() { calls = append(calls, "stopped") })
+	require.True(t, stopped.Stop())
+	require.False(t, stopped.Stop())
+
+	// Callbacks run synchronously, in deadline order
+	clk.Advance(5 * time.Second)
+	require.Equal(t, []string{"first", "second"}, calls)
+}
+
+func TestFake_Reset(t *testing.T) {
+	clk := NewFake(time.Unix(100, 0))
+	calls := 0
+
+	var timer interface{ Reset(time.Duration) bool }
+	timer = clk.AfterFunc(time.Second, func() {
+		calls++
+		if calls < 3 {
+			// Re-arming from the callback fires again within the same Advance
+			timer.Reset(time.Second)
+		}
+	})
+
+	clk.Advance(10 * time.Second)
+	require.Equal(t, 3, calls)
+	require.False(t, timer.Reset(time.Second))
+	require.Equal(t, 1, clk.Waiters())
+}
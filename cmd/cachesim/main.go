@@ -0,0 +1,126 @@
+// Command cachesim replays access traces through the eviction policies
+// registered in cache/policies, at several capacities, and reports the hit
+// ratio, eviction count and throughput of each combination. Policies are
+// driven directly, so the results are exact and repeatable.
+//
+// Usage:
+//
+//	cachesim [flags] [trace file]
+//
+// The trace is read from standard input if no file is given. Supported
+// formats are "text" (one key per line), "csv" (the key is in -column),
+// "arc" (ARC block-range traces) and "lirs" (LIRS block traces).
+//
+// Custom policies can be compared too: build a copy of this command that
+// imports a package registering them with policies.Register, and name them
+// in -policies.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/policies"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "cachesim:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// run is main without the process: it parses args, replays the trace and
+// writes the report to stdout.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("cachesim", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", formatText, "trace format: text, csv, arc or lirs")
+	column := flags.Int("column", 0, "zero-based key column, for csv traces")
+	header := flags.Bool("header", false, "skip the first row, for csv traces")
+	policyList := flags.String("policies", strings.Join(policies.Names(), ","), "comma-separated policies to compare")
+	capacityList := flags.String("capacities", "100,1000,10000", "comma-separated cache capacities")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *column < 0 {
+		return fmt.Errorf("invalid column %d", *column)
+	}
+	capacities, err := parseCapacities(*capacityList)
+	if err != nil {
+		return err
+	}
+	names := strings.Split(*policyList, ",")
+	factories := make([]cache.PolicyFactory[string], len(names))
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+		if factories[i], err = policies.Lookup[string](names[i]); err != nil {
+			return err
+		}
+	}
+
+	input := stdin
+	switch flags.NArg() {
+	case 0:
+	case 1:
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	default:
+		return errors.New("at most one trace file may be given")
+	}
+	trace, err := readTrace(input, traceOptions{format: *format, column: *column, header: *header})
+	if err != nil {
+		return fmt.Errorf("reading trace: %w", err)
+	}
+	if len(trace) == 0 {
+		return errors.New("the trace is empty")
+	}
+
+	var results []result
+	for _, capacity := range capacities {
+		for i, name := range names {
+			results = append(results, simulate(trace, name, factories[i], capacity))
+		}
+	}
+	return report(stdout, len(trace), results)
+}
+
+// parseCapacities parses a comma-separated list of positive capacities.
+func parseCapacities(list string) ([]int, error) {
+	var capacities []int
+	for _, field := range strings.Split(list, ",") {
+		capacity, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || capacity <= 0 {
+			return nil, fmt.Errorf("invalid capacity %q", field)
+		}
+		capacities = append(capacities, capacity)
+	}
+	return capacities, nil
+}
+
+// report writes the results as an aligned table.
+func report(w io.Writer, requests int, results []result) error {
+	fmt.Fprintf(w, "%d requests\n\n", requests)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "policy\tcapacity\thit ratio\tevictions\tops/s\t")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%d\t%.2f%%\t%d\t%.0f\t\n",
+			r.policy, r.capacity, 100*r.stats.HitRatio(), r.stats.Evictions, r.throughput())
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache/policies"
+)
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	trace := strings.NewReader("a\nb\na\nc\na\nb\n")
+	err := run([]string{"-policies", "lru, fifo", "-capacities", "1,2"}, trace, &stdout, &stderr)
+	require.NoError(t, err)
+	require.Empty(t, stderr.String())
+
+	out := stdout.String()
+	require.True(t, strings.HasPrefix(out, "6 requests\n"))
+	// Rows are grouped by capacity, in the order the policies were given.
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 7)
+	require.Regexp(t, `^\s+lru\s+1\s+0\.00%\s+5\s+\d+$`, lines[3])
+	require.Regexp(t, `^\s+fifo\s+1\s+0\.00%\s+5\s+\d+$`, lines[4])
+	require.Regexp(t, `^\s+lru\s+2\s+33\.33%\s+2\s+\d+$`, lines[5])
+	require.Regexp(t, `^\s+fifo\s+2\s+16\.67%\s+3\s+\d+$`, lines[6])
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want error
+		msg  string
+	}{
+		{name: "unknown policy", args: []string{"-policies", "arc"}, want: policies.ErrUnknownPolicy},
+		{name: "bad capacity", args: []string{"-capacities", "10,0"}, msg: `invalid capacity "0"`},
+		{name: "negative column", args: []string{"-format", "csv", "-column", "-1"}, msg: "invalid column -1"},
+		{name: "too many files", args: []string{"a", "b"}, msg: "at most one trace file"},
+		{name: "missing file", args: []string{"does-not-exist.trace"}, msg: "does-not-exist.trace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := run(tt.args, strings.NewReader("a\n"), &stdout, &stderr)
+			if tt.want != nil {
+				require.ErrorIs(t, err, tt.want)
+			} else {
+				require.ErrorContains(t, err, tt.msg)
+			}
+		})
+	}
+}
+
+func TestRunEmptyTrace(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run(nil, strings.NewReader("# nothing\n"), &stdout, &stderr)
+	require.ErrorContains(t, err, "the trace is empty")
+}
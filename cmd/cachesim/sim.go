@@ -0,0 +1,63 @@
+package main
+
+import (
+	"time"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+)
+
+// result is the outcome of replaying a trace through one configuration.
+type result struct {
+	policy   string
+	capacity int
+	stats    cache.Stats
+	elapsed  time.Duration
+}
+
+// throughput returns the number of requests replayed per second.
+func (r result) throughput() float64 {
+	if r.elapsed <= 0 {
+		return 0
+	}
+	return float64(r.stats.Requests()) / r.elapsed.Seconds()
+}
+
+// simulate replays a trace through a policy managing the given capacity, as
+// a read-through cache would see it: every miss is followed by an insert of
+// the missing key. It drives the policy directly, with its own record of the
+// resident keys, rather than through cache.Cache, whose read buffer may drop
+// hits under contention. The results are therefore exact and repeatable, and
+// the throughput is that of the policy alone.
+func simulate(trace []string, policy string, newPolicy cache.PolicyFactory[string], capacity int) result {
+	p := newPolicy()
+	resident := make(map[string]struct{}, capacity)
+	var stats cache.Stats
+
+	start := time.Now()
+	for _, key := range trace {
+		if _, ok := resident[key]; ok {
+			stats.Hits++
+			p.OnAccess(key)
+			continue
+		}
+		stats.Misses++
+		if len(resident) >= capacity {
+			victim := p.OnEvict()
+			if _, ok := resident[victim]; ok {
+				delete(resident, victim)
+				stats.Evictions++
+			}
+		}
+		resident[key] = struct{}{}
+		stats.Sets++
+		p.OnAdd(key)
+	}
+	elapsed := time.Since(start)
+
+	return result{
+		policy:   policy,
+		capacity: capacity,
+		stats:    stats,
+		elapsed:  elapsed,
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache/policies"
+	"github.com/Varun0157/in-mem-cache/workload"
+)
+
+func TestSimulate(t *testing.T) {
+	// Capacity 2, LRU: "a" is touched before "c" arrives, so "b" goes
+	trace := []string{"a", "b", "a", "c", "b", "a"}
+	r := simulate(trace, policies.LRU, policies.NewLRU[string], 2)
+	require.Equal(t, uint64(1), r.stats.Hits)
+	require.Equal(t, uint64(5), r.stats.Misses)
+	require.Equal(t, uint64(3), r.stats.Evictions)
+}
+
+func TestSimulateIsRepeatable(t *testing.T) {
+	keys := workload.Keys(workload.NewZipf(10_000, workload.DefaultSkew, 1), 100_000)
+	trace := make([]string, len(keys))
+	for i, key := range keys {
+		trace[i] = strconv.FormatUint(key, 10)
+	}
+
+	for _, name := range policies.Names() {
+		newPolicy, err := policies.Lookup[string](name)
+		require.NoError(t, err)
+
+		first := simulate(trace, name, newPolicy, 100)
+		for range 3 {
+			require.Equal(t, first.stats, simulate(trace, name, newPolicy, 100).stats, name)
+		}
+	}
+}
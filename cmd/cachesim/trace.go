@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Trace formats accepted by readTrace.
+const (
+	formatText = "text" // One key per line
+	formatCSV  = "csv"  // The key is one column of a CSV file
+	formatARC  = "arc"  // "start count ignored request" block ranges
+	formatLIRS = "lirs" // One block number per line
+)
+
+// maxARCRange bounds the number of blocks a single line of an ARC trace may
+// request. Real traces stay far below it; a larger count is a corrupt line,
+// which would otherwise make the simulator allocate without limit.
+const maxARCRange = 1 << 20
+
+// errEndOfTrace is returned by a line parser to stop reading a trace early.
+var errEndOfTrace = errors.New("end of trace")
+
+// traceOptions describe how to read a trace.
+type traceOptions struct {
+	format string
+	column int  // Key column, for CSV
+	header bool // Whether the CSV has a header row to skip
+}
+
+// readTrace reads the keys of an access trace in the given format.
+func readTrace(r io.Reader, opts traceOptions) ([]string, error) {
+	switch opts.format {
+	case formatText:
+		return readLines(r, func(line string, keys []string) ([]string, error) {
+			return append(keys, line), nil
+		})
+	case formatCSV:
+		return readCSV(r, opts.column, opts.header)
+	case formatARC:
+		return readLines(r, appendARC)
+	case formatLIRS:
+		return readLines(r, appendLIRS)
+	default:
+		return nil, fmt.Errorf("unknown trace format %q (want %s, %s, %s or %s)",
+			opts.format, formatText, formatCSV, formatARC, formatLIRS)
+	}
+}
+
+// readLines calls parse for every non-empty, non-comment line of r, until
+// parse returns errEndOfTrace.
+func readLines(r io.Reader, parse func(line string, keys []string) ([]string, error)) ([]string, error) {
+	var keys []string
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var err error
+		keys, err = parse(line, keys)
+		if errors.Is(err, errEndOfTrace) {
+			return keys, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	return keys, scanner.Err()
+}
+
+// appendARC expands a line of an ARC trace (Megiddo and Modha), which reads
+// "start count ignored request", into the blocks start to start+count-1.
+func appendARC(line string, keys []string) ([]string, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil, errors.New("want at least a starting block and a block count")
+	}
+	start, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("starting block: %w", err)
+	}
+	count, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("block count: %w", err)
+	}
+	if count > maxARCRange {
+		return nil, fmt.Errorf("block count %d exceeds %d", count, maxARCRange)
+	}
+	if count > 0 && start > math.MaxUint64-(count-1) {
+		return nil, fmt.Errorf("blocks %d to %d+%d-1 overflow", start, start, count)
+	}
+	for i := range count {
+		keys = append(keys, strconv.FormatUint(start+i, 10))
+	}
+	return keys, nil
+}
+
+// appendLIRS parses a line of a LIRS trace (Jiang and Zhang), which holds a
+// single block number. A "*" marks the end of the trace in some of them, and
+// stops reading.
+func appendLIRS(line string, keys []string) ([]string, error) {
+	if line == "*" {
+		return keys, errEndOfTrace
+	}
+	block, err := strconv.ParseUint(line, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("block: %w", err)
+	}
+	return append(keys, strconv.FormatUint(block, 10)), nil
+}
+
+// readCSV reads the given column of every record of a CSV file.
+func readCSV(r io.Reader, column int, header bool) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	var keys []string
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return keys, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if first && header {
+			continue
+		}
+		if column >= len(record) {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: no column %d", line, column)
+		}
+		keys = append(keys, record[column])
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTrace(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  traceOptions
+		input string
+		want  []string
+	}{
+		{
+			name:  "text skips blanks and comments",
+			opts:  traceOptions{format: formatText},
+			input: "# keys\na\n\n  b  \na\n",
+			want:  []string{"a", "b", "a"},
+		},
+		{
+			name:  "csv column with header",
+			opts:  traceOptions{format: formatCSV, column: 1, header: true},
+			input: "time,key\n1,x\n2,\"y,z\"\n3,x\n",
+			want:  []string{"x", "y,z", "x"},
+		},
+		{
+			name:  "arc expands block ranges",
+			opts:  traceOptions{format: formatARC},
+			input: "10 3 0 1\n7 1 0 2\n",
+			want:  []string{"10", "11", "12", "7"},
+		},
+		{
+			name:  "lirs stops at the end marker",
+			opts:  traceOptions{format: formatLIRS},
+			input: "5\n007\n*\n9\n",
+			want:  []string{"5", "7"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readTrace(strings.NewReader(tt.input), tt.opts)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReadTraceErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  traceOptions
+		input string
+		want  string
+	}{
+		{"unknown format", traceOptions{format: "parquet"}, "", `unknown trace format "parquet"`},
+		{"csv missing column", traceOptions{format: formatCSV, column: 2}, "a,b\n", "line 1: no column 2"},
+		{"arc short line", traceOptions{format: formatARC}, "1 1 0 0\n42\n", "line 2:"},
+		{"arc huge range", traceOptions{format: formatARC}, "0 18446744073709551615 0 0\n", "line 1: block count"},
+		{"arc overflow", traceOptions{format: formatARC}, "18446744073709551615 2 0 0\n", "line 1: blocks"},
+		{"lirs not a block", traceOptions{format: formatLIRS}, "x\n", "line 1: block"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readTrace(strings.NewReader(tt.input), tt.opts)
+			require.ErrorContains(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Package counter provides atomic int64 counters on top of the TTL cache,
+// for rate limits, quotas and similar read-modify-write workloads.
+package counter
+
+import (
+	"time"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/ttl"
+)
+
+// Cache is a set of int64 counters, created on first use. Counters live in
+// a ttl.Cache, so they are evicted by its core cache's policy and expire
+// like any other TTL entry.
+type Cache[K comparable] struct {
+	store *ttl.Cache[K, int64]
+}
+
+// New creates a counter cache that keeps its counters in store.
+func New[K comparable](store *ttl.Cache[K, int64]) *Cache[K] {
+	return &Cache[K]{store: store}
+}
+
+// Incr atomically adds delta to the counter for key, creating it at zero
+// with no expiration if needed, and returns the new value.
+func (c *Cache[K]) Incr(key K, delta int64) int64 {
+	return c.IncrWithTTL(key, delta, 0)
+}
+
+// Decr atomically subtracts delta from the counter for key, creating it at
+// zero with no expiration if needed, and returns the new value.
+func (c *Cache[K]) Decr(key K, delta int64) int64 {
+	return c.IncrWithTTL(key, -delta, 0)
+}
+
+// IncrWithTTL atomically adds delta to the counter for key and returns the
+// new value. A counter created by this call expires after ttl; an existing
+// counter keeps its expiration, so repeated calls count within a fixed window
+// that starts with the first one.
+func (c *Cache[K]) IncrWithTTL(key K, delta int64, ttl time.Duration) int64 {
+	value, _ := c.store.ComputeWithTTL(key, ttl, func(old int64, _ bool) (int64, cache.Op) {
+		return old + delta, cache.OpStore
+	})
+	return value
+}
+
+// Get returns the current value of the counter for key, if it exists.
+func (c *Cache[K]) Get(key K) (int64, bool) {
+	return c.store.Get(key)
+}
+
+// Delete removes the counter for key.
+func (c *Cache[K]) Delete(key K) {
+	c.store.Delete(key)
+}
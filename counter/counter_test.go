@@ -0,0 +1,84 @@
+package counter_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/policies"
+	"github.com/Varun0157/in-mem-cache/clock/clocktest"
+	"github.com/Varun0157/in-mem-cache/counter"
+	"github.com/Varun0157/in-mem-cache/ttl"
+)
+
+func TestCounter_IncrDecr(t *testing.T) {
+	core := cache.New[string, int64](10, policies.NewLRU[string]())
+	counters := counter.New(ttl.NewCache(core))
+
+	// Counters are created on first use
+	require.Equal(t, int64(5), counters.Incr("a", 5))
+	require.Equal(t, int64(7), counters.Incr("a", 2))
+	require.Equal(t, int64(4), counters.Decr("a", 3))
+	require.Equal(t, int64(-1), counters.Decr("b", 1))
+
+	val, found := counters.Get("a")
+	require.True(t, found)
+	require.Equal(t, int64(4), val)
+
+	counters.Delete("a")
+	_, found = counters.Get("a")
+	require.False(t, found)
+}
+
+func TestCounter_IncrWithTTL_FixedWindow(t *testing.T) {
+	core := cache.New[string, int64](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	counters := counter.New(ttl.NewCache(core, ttl.WithClock(clk)))
+
+	// The window starts with the first request and is not extended by later ones
+	require.Equal(t, int64(1), counters.IncrWithTTL("user:1", 1, time.Minute))
+	clk.Advance(40 * time.Second)
+	require.Equal(t, int64(2), counters.IncrWithTTL("user:1", 1, time.Minute))
+	clk.Advance(30 * time.Second)
+
+	// The window has closed, so counting starts again
+	require.Equal(t, int64(1), counters.IncrWithTTL("user:1", 1, time.Minute))
+}
+
+func TestCounter_FollowsEvictionPolicy(t *testing.T) {
+	core := cache.New[string, int64](2, policies.NewFIFO[string]())
+	counters := counter.New(ttl.NewCache(core))
+
+	counters.Incr("a", 1)
+	counters.Incr("b", 1)
+	counters.Incr("c", 1) // Evicts "a"
+
+	_, found := counters.Get("a")
+	require.False(t, found)
+	require.Equal(t, int64(1), counters.Incr("a", 1))
+}
+
+func TestCounter_ConcurrentIncr(t *testing.T) {
+	core := cache.New[string, int64](10, policies.NewLRU[string]())
+	counters := counter.New(ttl.NewCache(core))
+	var wg sync.WaitGroup
+	numGoroutines := 10
+	numOperations := 1000
+
+	for range numGoroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range numOperations {
+				counters.IncrWithTTL("hits", 1, time.Hour)
+			}
+		}()
+	}
+	wg.Wait()
+
+	val, _ := counters.Get("hits")
+	require.Equal(t, int64(numGoroutines*numOperations), val)
+}
@@ -1,6 +1,6 @@
 module github.com/Varun0157/in-mem-cache
 
-go 1.23.2
+go 1.24
 
 require github.com/stretchr/testify v1.10.0
 
@@ -0,0 +1,72 @@
+package hotkeys
+
+import (
+	"time"
+
+	"github.com/Varun0157/in-mem-cache/clock"
+)
+
+// Defaults used when no Options are given.
+const (
+	DefaultCapacity = 1024
+	DefaultWindow   = time.Minute
+	DefaultBuckets  = 6
+)
+
+// Option configures a Tracker created by New.
+type Option func(*config)
+
+// config holds the settings collected from Options.
+type config struct {
+	capacity int
+	window   time.Duration
+	buckets  int
+	clock    clock.Clock
+}
+
+// defaultConfig returns the settings used when no Options are given.
+func defaultConfig() config {
+	return config{
+		capacity: DefaultCapacity,
+		window:   DefaultWindow,
+		buckets:  DefaultBuckets,
+		clock:    clock.Real(),
+	}
+}
+
+// WithCapacity sets how many distinct keys each sub-window monitors, for
+// requests and misses each. It bounds the tracker's memory: keys whose share
+// of the traffic exceeds 1/capacity are always found. Non-positive values
+// are ignored.
+func WithCapacity(capacity int) Option {
+	return func(cfg *config) {
+		if capacity > 0 {
+			cfg.capacity = capacity
+		}
+	}
+}
+
+// WithWindow sets the length of the sliding window and the number of
+// sub-windows it is divided into. The window slides one sub-window at a
+// time, so more buckets make it smoother at the cost of more memory.
+// Non-positive values are ignored.
+func WithWindow(window time.Duration, buckets int) Option {
+	return func(cfg *config) {
+		if window > 0 {
+			cfg.window = window
+		}
+		if buckets > 0 {
+			cfg.buckets = buckets
+		}
+	}
+}
+
+// WithClock sets the source of time used to slide the window. It defaults to
+// the system clock; tests can pass a clocktest.Fake.
+func WithClock(clk clock.Clock) Option {
+	return func(cfg *config) {
+		if clk != nil {
+			cfg.clock = clk
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package hotkeys
+
+import "container/heap"
+
+// counter is the count of one monitored key in a summary.
+type counter[K comparable] struct {
+	key   K
+	count uint64
+	err   uint64 // Upper bound on how much count overestimates the truth
+	index int    // Position in the summary's heap
+}
+
+// summary is a Space-Saving summary: it monitors at most capacity keys and
+// finds every key whose frequency exceeds 1/capacity of the total, with
+// counts that overestimate by at most err. Its memory is bounded by
+// capacity, no matter how many distinct keys it sees.
+type summary[K comparable] struct {
+	capacity int
+	counters map[K]*counter[K]
+	byCount  counterHeap[K] // Min-heap, so the rarest key is replaced first
+}
+
+func newSummary[K comparable](capacity int) *summary[K] {
+	return &summary[K]{
+		capacity: capacity,
+		counters: make(map[K]*counter[K], capacity),
+		byCount:  make(counterHeap[K], 0, capacity),
+	}
+}
+
+// add counts one occurrence of key.
+func (s *summary[K]) add(key K) {
+	if c, ok := s.counters[key]; ok {
+		c.count++
+		heap.Fix(&s.byCount, c.index)
+		return
+	}
+
+	if len(s.byCount) < s.capacity {
+		c := &counter[K]{key: key, count: 1}
+		s.counters[key] = c
+		heap.Push(&s.byCount, c)
+		return
+	}
+
+	// Take over the counter of the rarest key, inheriting its count as the
+	// possible error of the new one.
+	c := s.byCount[0]
+	delete(s.counters, c.key)
+	c.key, c.err = key, c.count
+	c.count++
+	s.counters[key] = c
+	heap.Fix(&s.byCount, 0)
+}
+
+// reset forgets every key, keeping the allocated memory.
+func (s *summary[K]) reset() {
+	clear(s.counters)
+	clear(s.byCount)
+	s.byCount = s.byCount[:0]
+}
+
+// counterHeap orders counters by count, smallest first.
+type counterHeap[K comparable] []*counter[K]
+
+func (h counterHeap[K]) Len() int           { return len(h) }
+func (h counterHeap[K]) Less(i, j int) bool { return h[i].count < h[j].count }
+
+func (h counterHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *counterHeap[K]) Push(x any) {
+	c := x.(*counter[K])
+	c.index = len(*h)
+	*h = append(*h, c)
+}
+
+func (h *counterHeap[K]) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,44 @@
+package hotkeys
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary_BoundedMemory(t *testing.T) {
+	s := newSummary[int](8)
+	for i := range 10_000 {
+		s.add(i)
+	}
+	require.Len(t, s.counters, 8)
+	require.Len(t, s.byCount, 8)
+}
+
+func TestSummary_FindsFrequentKeys(t *testing.T) {
+	const capacity = 64
+	s := newSummary[uint64](capacity)
+	exact := make(map[uint64]uint64)
+	zipf := rand.NewZipf(rand.New(rand.NewPCG(1, 2)), 1.2, 1, 100_000)
+
+	const n = 200_000
+	for range n {
+		key := zipf.Uint64()
+		s.add(key)
+		exact[key]++
+	}
+
+	for key, count := range exact {
+		c, monitored := s.counters[key]
+		if count > n/capacity {
+			// Keys above the 1/capacity threshold are guaranteed to be found
+			require.True(t, monitored, "key %d seen %d times", key, count)
+		}
+		if monitored {
+			// Counts never underestimate, and the error bound holds
+			require.GreaterOrEqual(t, c.count, count)
+			require.LessOrEqual(t, c.count-c.err, count)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Package hotkeys finds the most requested and most missed keys of a cache
+// over a sliding window, in fixed memory, to show which keys are behind a
+// drop in the hit rate.
+package hotkeys
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/clock"
+)
+
+// KeyCount is a key and an estimate of how often it occurred in the window.
+// The true count lies between Count-Error and Count.
+type KeyCount[K comparable] struct {
+	Key   K
+	Count uint64
+	Error uint64
+}
+
+// Report is a snapshot of the hottest keys in the window, most frequent
+// first.
+type Report[K comparable] struct {
+	Requests []KeyCount[K] // Keys looked up most often
+	Misses   []KeyCount[K] // Keys looked up most often without being found
+}
+
+// Tracker is a decorator that counts the lookups made through it, and
+// reports the top keys with TopK. Memory is bounded by the capacity and
+// number of sub-windows, regardless of the number of distinct keys.
+type Tracker[K comparable, V any] struct {
+	coreCache cache.Cacheable[K, V]
+
+	clock clock.Clock
+	width time.Duration // Length of one sub-window
+
+	mu       sync.Mutex
+	requests []*summary[K] // Ring of sub-windows
+	misses   []*summary[K]
+	current  int       // Index of the sub-window being filled
+	start    time.Time // When the current sub-window started
+}
+
+// New creates a Tracker that wraps core. core may be nil if the tracker is
+// only fed through Record, in which case Get, Set, Delete and
+// AddRemovalListener must not be used.
+func New[K comparable, V any](core cache.Cacheable[K, V], opts ...Option) *Tracker[K, V] {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	t := &Tracker[K, V]{
+		coreCache: core,
+		clock:     cfg.clock,
+		width:     max(cfg.window/time.Duration(cfg.buckets), 1),
+		requests:  make([]*summary[K], cfg.buckets),
+		misses:    make([]*summary[K], cfg.buckets),
+		start:     cfg.clock.Now(),
+	}
+	for i := range t.requests {
+		t.requests[i] = newSummary[K](cfg.capacity)
+		t.misses[i] = newSummary[K](cfg.capacity)
+	}
+	return t
+}
+
+// Get retrieves a value from the core cache and records the lookup.
+func (t *Tracker[K, V]) Get(key K) (V, bool) {
+	value, found := t.coreCache.Get(key)
+	t.Record(key, found)
+	return value, found
+}
+
+// Set adds or updates a value in the core cache.
+func (t *Tracker[K, V]) Set(key K, value V) {
+	t.coreCache.Set(key, value)
+}
+
+// Delete removes a key from the core cache.
+func (t *Tracker[K, V]) Delete(key K) {
+	t.coreCache.Delete(key)
+}
+
+// AddRemovalListener registers a listener with the core cache.
+func (t *Tracker[K, V]) AddRemovalListener(listener cache.RemovalListener[K, V]) {
+	t.coreCache.AddRemovalListener(listener)
+}
+
+// Record counts a lookup of key, and a miss unless hit is true. It is called
+// by Get, and can be used to feed the tracker from lookups made elsewhere.
+func (t *Tracker[K, V]) Record(key K, hit bool) {
+	now := t.clock.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.slideLocked(now)
+	t.requests[t.current].add(key)
+	if !hit {
+		t.misses[t.current].add(key)
+	}
+}
+
+// TopK returns up to k of the most requested and most missed keys in the
+// window.
+func (t *Tracker[K, V]) TopK(k int) Report[K] {
+	now := t.clock.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.slideLocked(now)
+	return Report[K]{
+		Requests: topK(t.requests, k),
+		Misses:   topK(t.misses, k),
+	}
+}
+
+// Reset forgets every lookup recorded so far.
+func (t *Tracker[K, V]) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i := range t.requests {
+		t.requests[i].reset()
+		t.misses[i].reset()
+	}
+}
+
+// slideLocked moves the window forward to now, clearing the sub-windows that
+// fall out of it. The caller must hold t.mu.
+func (t *Tracker[K, V]) slideLocked(now time.Time) {
+	elapsed := now.Sub(t.start) / t.width
+	if elapsed <= 0 {
+		return
+	}
+	for i := 0; i < min(int(elapsed), len(t.requests)); i++ {
+		t.current = (t.current + 1) % len(t.requests)
+		t.requests[t.current].reset()
+		t.misses[t.current].reset()
+	}
+	t.start = t.start.Add(elapsed * t.width)
+}
+
+// topK merges the sub-windows of a ring and returns its k most frequent
+// keys.
+func topK[K comparable](ring []*summary[K], k int) []KeyCount[K] {
+	if k <= 0 {
+		return nil
+	}
+
+	merged := make(map[K]*KeyCount[K])
+	for _, s := range ring {
+		for key, c := range s.counters {
+			kc, ok := merged[key]
+			if !ok {
+				kc = &KeyCount[K]{Key: key}
+				merged[key] = kc
+			}
+			kc.Count += c.count
+			kc.Error += c.err
+		}
+	}
+
+	// A full sub-window may have seen a key it no longer monitors up to as
+	// many times as its rarest monitored key.
+	for _, s := range ring {
+		if len(s.byCount) < s.capacity {
+			continue
+		}
+		floor := s.byCount[0].count
+		for key, kc := range merged {
+			if _, ok := s.counters[key]; !ok {
+				kc.Count += floor
+				kc.Error += floor
+			}
+		}
+	}
+
+	top := make([]KeyCount[K], 0, len(merged))
+	for _, kc := range merged {
+		top = append(top, *kc)
+	}
+	slices.SortFunc(top, func(a, b KeyCount[K]) int {
+		// Most frequent first, and the most certain first among equals
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Error, b.Error)
+	})
+	return top[:min(k, len(top))]
+}
+
+// Static assertion to ensure *hotkeys.Tracker satisfies the cache.Cacheable interface.
+var _ cache.Cacheable[any, any] = (*Tracker[any, any])(nil)
@@ -0,0 +1,112 @@
+package hotkeys_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/clock/clocktest"
+	"github.com/Varun0157/in-mem-cache/hotkeys"
+)
+
+// keys returns the keys of a top-K list, in order.
+func keys[K comparable](counts []hotkeys.KeyCount[K]) []K {
+	out := make([]K, len(counts))
+	for i, kc := range counts {
+		out[i] = kc.Key
+	}
+	return out
+}
+
+func TestTracker_TopK(t *testing.T) {
+	core := cache.New[string, int](10, nil)
+	clk := clocktest.NewFake(time.Unix(0, 0))
+	tracker := hotkeys.New(core, hotkeys.WithClock(clk))
+
+	tracker.Set("hot", 1)
+	tracker.Set("warm", 2)
+	for range 5 {
+		tracker.Get("hot")
+	}
+	for range 4 {
+		tracker.Get("warm")
+	}
+	for range 3 {
+		tracker.Get("missing")
+	}
+	tracker.Get("gone")
+
+	report := tracker.TopK(2)
+	require.Equal(t, []string{"hot", "warm"}, keys(report.Requests))
+	require.Equal(t, uint64(5), report.Requests[0].Count)
+
+	require.Equal(t, []string{"missing", "gone"}, keys(report.Misses))
+	require.Equal(t, uint64(3), report.Misses[0].Count)
+	require.Zero(t, report.Misses[0].Error)
+
+	// The tracker is transparent to the cache
+	value, found := tracker.Get("warm")
+	require.True(t, found)
+	require.Equal(t, 2, value)
+	tracker.Delete("warm")
+	_, found = core.Get("warm")
+	require.False(t, found)
+}
+
+func TestTracker_SlidingWindow(t *testing.T) {
+	clk := clocktest.NewFake(time.Unix(0, 0))
+	tracker := hotkeys.New[string, int](nil,
+		hotkeys.WithClock(clk),
+		hotkeys.WithWindow(time.Minute, 6),
+	)
+
+	for range 10 {
+		tracker.Record("old", false)
+	}
+	clk.Advance(30 * time.Second)
+	for range 5 {
+		tracker.Record("new", true)
+	}
+
+	report := tracker.TopK(10)
+	require.Equal(t, []string{"old", "new"}, keys(report.Requests))
+	require.Equal(t, []string{"old"}, keys(report.Misses))
+
+	// Once "old" has slid out of the window, only "new" is left
+	clk.Advance(40 * time.Second)
+	report = tracker.TopK(10)
+	require.Equal(t, []string{"new"}, keys(report.Requests))
+	require.Empty(t, report.Misses)
+
+	// Long idle periods clear everything
+	clk.Advance(time.Hour)
+	require.Empty(t, tracker.TopK(10).Requests)
+}
+
+func TestTracker_FixedMemory(t *testing.T) {
+	clk := clocktest.NewFake(time.Unix(0, 0))
+	tracker := hotkeys.New[int, int](nil,
+		hotkeys.WithClock(clk),
+		hotkeys.WithCapacity(16),
+		hotkeys.WithWindow(time.Minute, 1),
+	)
+
+	// A heavy hitter among a long tail of one-off keys
+	for i := range 10_000 {
+		tracker.Record(i, true)
+		if i%4 == 0 {
+			tracker.Record(-1, false)
+		}
+	}
+
+	report := tracker.TopK(1)
+	require.Equal(t, []int{-1}, keys(report.Requests))
+	require.Equal(t, []int{-1}, keys(report.Misses))
+	require.GreaterOrEqual(t, report.Requests[0].Count, uint64(2500))
+	require.Len(t, tracker.TopK(100).Requests, 16)
+
+	tracker.Reset()
+	require.Empty(t, tracker.TopK(1).Requests)
+}
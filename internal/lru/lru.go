@@ -0,0 +1,75 @@
+// Package lru implements the least-recently-used eviction policy. It lives
+// here, rather than in cache/policies, so that the cache package can fall
+// back to it without an import cycle; callers should use policies.NewLRU.
+package lru
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Policy is an LRU eviction policy. It satisfies cache.EvictionPolicy.
+type Policy[K comparable] struct {
+	mu     sync.RWMutex
+	keys   *list.List
+	keyMap map[K]*list.Element
+}
+
+// New creates a new LRU eviction policy.
+func New[K comparable]() *Policy[K] {
+	return &Policy[K]{
+		keys:   list.New(),
+		keyMap: make(map[K]*list.Element),
+	}
+}
+
+// String returns the name of the policy, as used by policies.Lookup.
+func (p *Policy[K]) String() string {
+	return "lru"
+}
+
+func (p *Policy[K]) OnAdd(key K) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if element, exists := p.keyMap[key]; exists {
+		p.keys.MoveToBack(element)
+	} else {
+		element := p.keys.PushBack(key)
+		p.keyMap[key] = element
+	}
+}
+
+func (p *Policy[K]) OnAccess(key K) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if element, exists := p.keyMap[key]; exists {
+		p.keys.MoveToBack(element)
+	}
+}
+
+func (p *Policy[K]) OnRemove(key K) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if element, exists := p.keyMap[key]; exists {
+		p.keys.Remove(element)
+		delete(p.keyMap, key)
+	}
+}
+
+func (p *Policy[K]) OnEvict() K {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	element := p.keys.Front()
+	if element == nil {
+		var zero K
+		return zero
+	}
+	key := element.Value.(K)
+	p.keys.Remove(element)
+	delete(p.keyMap, key)
+	return key
+}
@@ -0,0 +1,266 @@
+// Package scheduler provides a hierarchical timing wheel for deferred work
+// keyed by comparable values. It is shared by the decorators in this module
+// that need to act on keys at (or shortly after) a point in time.
+package scheduler
+
+import (
+	"container/list"
+	"time"
+)
+
+const (
+	// slotBits is the number of bits of the tick counter covered by one level.
+	slotBits = 6
+	// slotsPerLevel is the number of buckets in every level of the wheel.
+	slotsPerLevel = 1 << slotBits
+	slotMask      = slotsPerLevel - 1
+	// numLevels bounds the horizon of the wheel to slotsPerLevel^numLevels ticks.
+	// Deadlines beyond the horizon are parked in the top level and re-filed
+	// as the wheel turns.
+	numLevels = 5
+)
+
+// DefaultTick is the tick resolution used when New is given a non-positive tick.
+const DefaultTick = 10 * time.Millisecond
+
+// entry is a scheduled key and its position in the wheel.
+type entry[K comparable] struct {
+	key      K
+	deadline time.Time
+	tick     uint64 // first tick at which the entry is due
+
+	level   int
+	slot    int
+	element *list.Element
+}
+
+// Wheel is a hierarchical timing wheel. Scheduling, cancelling and firing
+// a key are O(1); advancing the wheel is amortised O(1) per elapsed tick
+// plus the number of keys that come due.
+//
+// Wheel is not safe for concurrent use; callers are expected to guard it
+// with their own lock.
+type Wheel[K comparable] struct {
+	tick   time.Duration
+	origin time.Time
+	now    uint64 // current tick, relative to origin
+
+	levels  [numLevels][slotsPerLevel]*list.List
+	counts  [numLevels]int // number of entries filed in each level
+	overdue *list.List     // entries that were already due when scheduled
+	entries map[K]*entry[K]
+}
+
+// New creates a Wheel that starts turning at start with the given tick
+// resolution. Keys never fire before their deadline, but may fire up to one
+// tick after it.
+func New[K comparable](tick time.Duration, start time.Time) *Wheel[K] {
+	if tick <= 0 {
+		tick = DefaultTick
+	}
+	w := &Wheel[K]{
+		tick:    tick,
+		origin:  start,
+		overdue: list.New(),
+		entries: make(map[K]*entry[K]),
+	}
+	for l := range w.levels {
+		for s := range w.levels[l] {
+			w.levels[l][s] = list.New()
+		}
+	}
+	return w
+}
+
+// Len returns the number of scheduled keys.
+func (w *Wheel[K]) Len() int {
+	return len(w.entries)
+}
+
+// Deadline returns the deadline a key is scheduled for, if any.
+func (w *Wheel[K]) Deadline(key K) (time.Time, bool) {
+	e, ok := w.entries[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	return e.deadline, true
+}
+
+// Schedule arranges for key to fire at deadline, replacing any deadline
+// previously scheduled for it.
+func (w *Wheel[K]) Schedule(key K, deadline time.Time) {
+	if e, ok := w.entries[key]; ok {
+		w.unlink(e)
+		e.deadline = deadline
+		e.tick = w.tickOf(deadline)
+		w.insert(e)
+		return
+	}
+
+	e := &entry[K]{key: key, deadline: deadline, tick: w.tickOf(deadline)}
+	w.entries[key] = e
+	w.insert(e)
+}
+
+// Cancel removes a key from the wheel. It reports whether the key was scheduled.
+func (w *Wheel[K]) Cancel(key K) bool {
+	e, ok := w.entries[key]
+	if !ok {
+		return false
+	}
+	w.unlink(e)
+	delete(w.entries, key)
+	return true
+}
+
+// Advance turns the wheel forward to now and calls fire for every key whose
+// deadline has passed. Fired keys are removed from the wheel before fire is
+// called, so fire may safely reschedule them.
+func (w *Wheel[K]) Advance(now time.Time, fire func(key K)) {
+	w.fireOverdue(fire)
+
+	target := w.elapsed(now)
+	if len(w.entries) == 0 {
+		// Nothing can come due, so skip the intermediate ticks entirely.
+		if target > w.now {
+			w.now = target
+		}
+		return
+	}
+
+	for w.now < target {
+		w.skipIdle(target)
+		w.now++
+		w.cascade()
+		w.fireList(w.levels[0][w.now&slotMask], fire)
+		w.fireOverdue(fire)
+		if len(w.entries) == 0 && target > w.now {
+			w.now = target
+		}
+	}
+}
+
+// skipIdle moves the wheel to just before the next tick at which anything
+// can fire or cascade, without passing target. Only the lowest occupied
+// level matters: everything below it is empty, and it is next visited at
+// the following multiple of its span.
+func (w *Wheel[K]) skipIdle(target uint64) {
+	level := 0
+	for level < numLevels-1 && w.counts[level] == 0 {
+		level++
+	}
+	if level == 0 {
+		return
+	}
+	span := uint64(1) << (slotBits * level)
+	next := (w.now/span+1)*span - 1
+	w.now = min(next, target-1)
+}
+
+// cascade re-files the entries of every higher-level bucket whose span
+// starts at the current tick into the lower levels.
+func (w *Wheel[K]) cascade() {
+	for l := numLevels - 1; l > 0; l-- {
+		if w.now&(uint64(1)<<(slotBits*l)-1) != 0 {
+			continue
+		}
+		bucket := w.levels[l][(w.now>>(slotBits*l))&slotMask]
+		for bucket.Len() > 0 {
+			e := bucket.Remove(bucket.Front()).(*entry[K])
+			w.counts[l]--
+			w.insert(e)
+		}
+	}
+}
+
+// fireOverdue fires the entries that were already due when they were filed.
+// The list is detached first so that keys rescheduled into the past by fire
+// wait for the next turn instead of looping forever.
+func (w *Wheel[K]) fireOverdue(fire func(key K)) {
+	if w.overdue.Len() == 0 {
+		return
+	}
+	overdue := w.overdue
+	w.overdue = list.New()
+	w.fireList(overdue, fire)
+}
+
+// fireList removes every due entry in bucket from the wheel and fires it.
+// Entries parked in the bucket ahead of their time are re-filed instead.
+// The bucket is consumed from the front, since fire may cancel or
+// reschedule other entries while it is being walked.
+func (w *Wheel[K]) fireList(bucket *list.List, fire func(key K)) {
+	for bucket.Len() > 0 {
+		element := bucket.Front()
+		e := bucket.Remove(element).(*entry[K])
+		if w.entries[e.key] != e || e.element != element {
+			// Cancelled or moved by fire while sitting in a detached list.
+			continue
+		}
+		if e.level >= 0 {
+			w.counts[e.level]--
+		}
+		if e.tick > w.now {
+			w.insert(e)
+			continue
+		}
+		delete(w.entries, e.key)
+		fire(e.key)
+	}
+}
+
+// insert files an entry into the bucket that will next bring it due.
+func (w *Wheel[K]) insert(e *entry[K]) {
+	if e.tick <= w.now {
+		e.level, e.slot = -1, -1
+		e.element = w.overdue.PushBack(e)
+		return
+	}
+
+	// The entry belongs to the lowest level at which it shares all higher
+	// bits with the current tick.
+	level := 0
+	for level < numLevels-1 && e.tick>>(slotBits*(level+1)) != w.now>>(slotBits*(level+1)) {
+		level++
+	}
+
+	shift := slotBits * level
+	slot := int((e.tick >> shift) & slotMask)
+	if level == numLevels-1 && (e.tick>>shift)-(w.now>>shift) >= slotsPerLevel {
+		// Beyond the horizon: park it in the last top-level bucket to be
+		// visited, and re-file it from there.
+		slot = int(((w.now >> shift) + slotMask) & slotMask)
+	}
+
+	e.level, e.slot = level, slot
+	e.element = w.levels[level][slot].PushBack(e)
+	w.counts[level]++
+}
+
+// unlink removes an entry from whichever bucket currently holds it.
+func (w *Wheel[K]) unlink(e *entry[K]) {
+	if e.level < 0 {
+		w.overdue.Remove(e.element)
+		return
+	}
+	w.levels[e.level][e.slot].Remove(e.element)
+	w.counts[e.level]--
+}
+
+// elapsed converts a point in time to the last tick that has fully passed.
+func (w *Wheel[K]) elapsed(t time.Time) uint64 {
+	d := t.Sub(w.origin)
+	if d <= 0 {
+		return 0
+	}
+	return uint64(d / w.tick)
+}
+
+// tickOf converts a deadline to the first tick at which it has passed.
+func (w *Wheel[K]) tickOf(t time.Time) uint64 {
+	d := t.Sub(w.origin)
+	if d <= 0 {
+		return 0
+	}
+	return uint64((d + w.tick - 1) / w.tick)
+}
@@ -0,0 +1,190 @@
+package scheduler
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// collect advances the wheel to now and returns the keys that fired.
+func collect[K comparable](w *Wheel[K], now time.Time) []K {
+	var fired []K
+	w.Advance(now, func(key K) {
+		fired = append(fired, key)
+	})
+	return fired
+}
+
+func TestWheel_FiresInDeadlineOrder(t *testing.T) {
+	start := time.Unix(0, 0)
+	w := New[string](time.Millisecond, start)
+
+	w.Schedule("c", start.Add(30*time.Millisecond))
+	w.Schedule("a", start.Add(10*time.Millisecond))
+	w.Schedule("b", start.Add(20*time.Millisecond))
+	require.Equal(t, 3, w.Len())
+
+	// Nothing is due yet
+	require.Empty(t, collect(w, start.Add(9*time.Millisecond)))
+
+	require.Equal(t, []string{"a"}, collect(w, start.Add(10*time.Millisecond)))
+	require.Equal(t, []string{"b", "c"}, collect(w, start.Add(35*time.Millisecond)))
+	require.Equal(t, 0, w.Len())
+}
+
+func TestWheel_NeverFiresEarly(t *testing.T) {
+	start := time.Unix(0, 0)
+	w := New[string](10*time.Millisecond, start)
+
+	// The deadline falls in the middle of a tick
+	w.Schedule("a", start.Add(15*time.Millisecond))
+
+	require.Empty(t, collect(w, start.Add(14*time.Millisecond)))
+	require.Empty(t, collect(w, start.Add(19*time.Millisecond)))
+	require.Equal(t, []string{"a"}, collect(w, start.Add(20*time.Millisecond)))
+}
+
+func TestWheel_Cascade(t *testing.T) {
+	start := time.Unix(0, 0)
+	w := New[int](time.Millisecond, start)
+
+	// Deadlines spread across several levels of the wheel
+	deadlines := map[int]time.Duration{
+		1: 50 * time.Millisecond,
+		2: 500 * time.Millisecond,
+		3: 5 * time.Second,
+		4: 5 * time.Minute,
+		5: 5 * time.Hour,
+	}
+	for key, d := range deadlines {
+		w.Schedule(key, start.Add(d))
+	}
+
+	for key := 1; key <= 5; key++ {
+		d := deadlines[key]
+		require.Empty(t, collect(w, start.Add(d-time.Millisecond)), "key %d fired early", key)
+		require.Equal(t, []int{key}, collect(w, start.Add(d)), "key %d did not fire", key)
+	}
+}
+
+func TestWheel_BeyondHorizon(t *testing.T) {
+	start := time.Unix(0, 0)
+	w := New[string](time.Millisecond, start)
+
+	// Far beyond slotsPerLevel^numLevels ticks
+	far := start.Add(24 * 24 * time.Hour)
+	w.Schedule("far", far)
+	w.Schedule("near", start.Add(time.Millisecond))
+
+	require.Equal(t, []string{"near"}, collect(w, start.Add(time.Hour)))
+	require.Empty(t, collect(w, far.Add(-time.Millisecond)))
+	require.Equal(t, []string{"far"}, collect(w, far))
+}
+
+func TestWheel_RescheduleAndCancel(t *testing.T) {
+	start := time.Unix(0, 0)
+	w := New[string](time.Millisecond, start)
+
+	w.Schedule("a", start.Add(10*time.Millisecond))
+	w.Schedule("b", start.Add(10*time.Millisecond))
+
+	// Push "a" further out and drop "b" entirely
+	w.Schedule("a", start.Add(100*time.Millisecond))
+	require.True(t, w.Cancel("b"))
+	require.False(t, w.Cancel("b"))
+
+	deadline, ok := w.Deadline("a")
+	require.True(t, ok)
+	require.Equal(t, start.Add(100*time.Millisecond), deadline)
+
+	_, ok = w.Deadline("b")
+	require.False(t, ok)
+
+	require.Empty(t, collect(w, start.Add(50*time.Millisecond)))
+	require.Equal(t, []string{"a"}, collect(w, start.Add(100*time.Millisecond)))
+}
+
+func TestWheel_ScheduleInThePast(t *testing.T) {
+	start := time.Unix(0, 0)
+	w := New[string](time.Millisecond, start)
+
+	collect(w, start.Add(time.Second))
+	w.Schedule("late", start)
+
+	// Already-due keys fire on the next turn, even without time passing
+	require.Equal(t, []string{"late"}, collect(w, start.Add(time.Second)))
+}
+
+func TestWheel_FireCanReschedule(t *testing.T) {
+	start := time.Unix(0, 0)
+	w := New[string](time.Millisecond, start)
+
+	w.Schedule("a", start.Add(time.Millisecond))
+	w.Advance(start.Add(time.Millisecond), func(key string) {
+		// Re-arm the key from inside the callback
+		w.Schedule(key, start.Add(10*time.Millisecond))
+	})
+
+	require.Equal(t, 1, w.Len())
+	require.Equal(t, []string{"a"}, collect(w, start.Add(10*time.Millisecond)))
+}
+
+func TestWheel_MatchesNaiveScan(t *testing.T) {
+	start := time.Unix(0, 0)
+	w := New[int](time.Millisecond, start)
+	deadlines := make(map[int]time.Time)
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	now := start
+	for step := range 5000 {
+		key := rng.IntN(500)
+		switch rng.IntN(4) {
+		case 0:
+			delete(deadlines, key)
+			w.Cancel(key)
+		default:
+			// Mix of short and very long deadlines
+			d := time.Duration(rng.Int64N(int64(time.Second)))
+			if rng.IntN(10) == 0 {
+				d = time.Duration(rng.Int64N(int64(1000 * time.Hour)))
+			}
+			deadlines[key] = now.Add(d)
+			w.Schedule(key, now.Add(d))
+		}
+
+		now = now.Add(time.Duration(rng.Int64N(int64(10 * time.Millisecond))))
+		if step%1000 == 999 {
+			// Occasionally jump far ahead
+			now = now.Add(100 * time.Hour)
+		}
+
+		fired := make(map[int]bool)
+		w.Advance(now, func(key int) {
+			fired[key] = true
+		})
+		for key, deadline := range deadlines {
+			// Deadlines are honoured at tick resolution, rounding up
+			ticks := (deadline.Sub(start) + time.Millisecond - 1) / time.Millisecond
+			due := !start.Add(ticks * time.Millisecond).After(now)
+			require.Equal(t, due, fired[key], "key %d, deadline %v, now %v", key, deadline, now)
+			if due {
+				delete(deadlines, key)
+			}
+		}
+		require.Equal(t, len(deadlines), w.Len())
+	}
+}
+
+func BenchmarkWheel_ScheduleAdvance(b *testing.B) {
+	start := time.Unix(0, 0)
+	w := New[int](time.Millisecond, start)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		now := start.Add(time.Duration(i) * time.Microsecond)
+		w.Schedule(i, now.Add(time.Second))
+		w.Advance(now, func(int) {})
+	}
+}
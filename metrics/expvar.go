@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"expvar"
+	"runtime/metrics"
+	"sync"
+)
+
+// ExpvarName is the name under which PublishExpvar publishes the Default
+// registry.
+const ExpvarName = "inmemcache"
+
+// runtimeMetrics are the runtime/metrics samples published alongside the
+// caches, to put their sizes in the context of the heap.
+var runtimeMetrics = []string{
+	"/memory/classes/heap/objects:bytes",
+	"/memory/classes/total:bytes",
+	"/gc/heap/objects:objects",
+	"/gc/heap/goal:bytes",
+	"/gc/cycles/total:gc-cycles",
+}
+
+var publishOnce sync.Once
+
+// PublishExpvar publishes the Default registry as a single expvar named
+// "inmemcache", so that the stats of every registered cache appear on
+// /debug/vars, next to a sample of the Go runtime's heap metrics. It is
+// opt-in and may be called any number of times.
+//
+// expvar cannot unpublish a variable, so caches come and go through the
+// registry instead: registering a cache makes it visible, and closing or
+// unregistering it removes it.
+func PublishExpvar() {
+	publishOnce.Do(func() {
+		expvar.Publish(ExpvarName, Default.Expvar())
+	})
+}
+
+// Expvar returns an expvar.Var that renders the registry as JSON, for use
+// with expvar.Publish under a name of your choosing.
+func (r *Registry) Expvar() expvar.Var {
+	return expvar.Func(func() any {
+		return r.expvarValue()
+	})
+}
+
+// expvarStats is the JSON form of a cache's stats.
+type expvarStats struct {
+	Hits          uint64  `json:"hits"`
+	Misses        uint64  `json:"misses"`
+	HitRatio      float64 `json:"hit_ratio"`
+	Sets          uint64  `json:"sets"`
+	Updates       uint64  `json:"updates"`
+	Deletes       uint64  `json:"deletes"`
+	Evictions     uint64  `json:"evictions"`
+	Expirations   uint64  `json:"expirations"`
+	LoadSuccesses uint64  `json:"load_successes"`
+	LoadFailures  uint64  `json:"load_failures"`
+	LoadTimeNanos int64   `json:"load_time_ns"`
+	Entries       *int    `json:"entries,omitempty"`
+	Weight        *int    `json:"weight,omitempty"`
+}
+
+// expvarValue is the value rendered by Expvar.
+type expvarValue struct {
+	Caches  map[string]expvarStats `json:"caches"`
+	Runtime map[string]any         `json:"runtime"`
+}
+
+func (r *Registry) expvarValue() expvarValue {
+	samples := r.collect()
+	value := expvarValue{
+		Caches:  make(map[string]expvarStats, len(samples)),
+		Runtime: readRuntimeMetrics(),
+	}
+	for _, s := range samples {
+		stats := expvarStats{
+			Hits:          s.stats.Hits,
+			Misses:        s.stats.Misses,
+			HitRatio:      s.stats.HitRatio(),
+			Sets:          s.stats.Sets,
+			Updates:       s.stats.Updates,
+			Deletes:       s.stats.Deletes,
+			Evictions:     s.stats.Evictions,
+			Expirations:   s.stats.Expirations,
+			LoadSuccesses: s.stats.LoadSuccesses,
+			LoadFailures:  s.stats.LoadFailures,
+			LoadTimeNanos: int64(s.stats.LoadTime),
+		}
+		if s.hasLen {
+			stats.Entries = &s.len
+		}
+		if s.hasWeight {
+			stats.Weight = &s.weight
+		}
+		value.Caches[s.name] = stats
+	}
+	return value
+}
+
+// readRuntimeMetrics samples runtimeMetrics. Metrics the running Go version
+// does not support are left out.
+func readRuntimeMetrics() map[string]any {
+	samples := make([]metrics.Sample, len(runtimeMetrics))
+	for i, name := range runtimeMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	values := make(map[string]any, len(samples))
+	for _, sample := range samples {
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			values[sample.Name] = sample.Value.Uint64()
+		case metrics.KindFloat64:
+			values[sample.Name] = sample.Value.Float64()
+		}
+	}
+	return values
+}
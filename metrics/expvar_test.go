@@ -0,0 +1,94 @@
+package metrics_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/metrics"
+	"github.com/Varun0157/in-mem-cache/ttl"
+)
+
+// vars is the part of the inmemcache expvar that the tests look at.
+type vars struct {
+	Caches map[string]struct {
+		Hits     uint64  `json:"hits"`
+		Misses   uint64  `json:"misses"`
+		HitRatio float64 `json:"hit_ratio"`
+		Entries  *int    `json:"entries"`
+	} `json:"caches"`
+	Runtime map[string]float64 `json:"runtime"`
+}
+
+func TestRegistry_Expvar(t *testing.T) {
+	c, err := cache.NewWithOptions(
+		cache.WithCapacity[string, int](10),
+		cache.WithStats[string, int](),
+	)
+	require.NoError(t, err)
+	c.Set("a", 1)
+	c.Get("a")
+	c.Get("b")
+
+	registry := metrics.NewRegistry()
+	require.NoError(t, registry.Register("users", c))
+
+	var got vars
+	require.NoError(t, json.Unmarshal([]byte(registry.Expvar().String()), &got))
+
+	users := got.Caches["users"]
+	require.Equal(t, uint64(1), users.Hits)
+	require.Equal(t, uint64(1), users.Misses)
+	require.Equal(t, 0.5, users.HitRatio)
+	require.NotNil(t, users.Entries)
+	require.Equal(t, 1, *users.Entries)
+
+	// Heap data from runtime/metrics is sampled alongside
+	require.Positive(t, got.Runtime["/memory/classes/heap/objects:bytes"])
+}
+
+func TestPublishExpvar(t *testing.T) {
+	metrics.PublishExpvar()
+	metrics.PublishExpvar() // Publishing twice is harmless
+	require.NotNil(t, expvar.Get(metrics.ExpvarName))
+
+	c := ttl.NewCache(cache.New[string, int](10, nil), ttl.WithStats())
+	require.NoError(t, metrics.Register("sessions", c))
+
+	// The cache shows up on /debug/vars
+	recorder := httptest.NewRecorder()
+	expvar.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/vars", nil))
+	var all map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &all))
+	var got vars
+	require.NoError(t, json.Unmarshal(all[metrics.ExpvarName], &got))
+	require.Contains(t, got.Caches, "sessions")
+
+	// Closing the cache unregisters it, which frees the name
+	c.Close()
+	got = vars{}
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get(metrics.ExpvarName).String()), &got))
+	require.NotContains(t, got.Caches, "sessions")
+	require.False(t, metrics.Unregister("sessions"))
+}
+
+func TestRegistry_CloseDoesNotUnregisterSuccessor(t *testing.T) {
+	registry := metrics.NewRegistry()
+	first := cache.New[string, int](1, nil)
+	second := cache.New[string, int](1, nil)
+
+	require.NoError(t, registry.Register("c", first))
+	require.True(t, registry.Unregister("c"))
+	require.NoError(t, registry.Register("c", second))
+
+	// The old cache closing must not take its successor's name away
+	first.Close()
+	require.ErrorIs(t, registry.Register("c", second), metrics.ErrDuplicateName)
+
+	second.Close()
+	require.NoError(t, registry.Register("c", second))
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+)
+
+// contentType is that of version 0.0.4 of the Prometheus text format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// counter describes a counter family derived from cache.Stats.
+type counter struct {
+	name  string
+	help  string
+	value func(cache.Stats) uint64
+}
+
+var counters = []counter{
+	{"inmemcache_hits_total", "Lookups that found a value.", func(s cache.Stats) uint64 { return s.Hits }},
+	{"inmemcache_misses_total", "Lookups that did not find a value.", func(s cache.Stats) uint64 { return s.Misses }},
+	{"inmemcache_sets_total", "Writes that added an entry.", func(s cache.Stats) uint64 { return s.Sets }},
+	{"inmemcache_updates_total", "Writes that replaced an existing value.", func(s cache.Stats) uint64 { return s.Updates }},
+	{"inmemcache_deletes_total", "Entries removed explicitly.", func(s cache.Stats) uint64 { return s.Deletes }},
+	{"inmemcache_evictions_total", "Entries evicted to make room.", func(s cache.Stats) uint64 { return s.Evictions }},
+	{"inmemcache_expirations_total", "Entries removed because they expired.", func(s cache.Stats) uint64 { return s.Expirations }},
+	{"inmemcache_load_failures_total", "Loader calls that returned an error.", func(s cache.Stats) uint64 { return s.LoadFailures }},
+}
+
+// Handler returns an http.Handler that renders the statistics of every
+// cache in the registry in the Prometheus text exposition format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		r.WritePrometheus(w)
+	})
+}
+
+// Handler returns a Prometheus handler for the Default registry.
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// WritePrometheus writes the statistics of every cache in the registry to w
+// in the Prometheus text exposition format.
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	samples := r.collect()
+	bw := bufio.NewWriter(w)
+
+	for _, c := range counters {
+		writeHeader(bw, c.name, c.help, "counter")
+		for _, s := range samples {
+			fmt.Fprintf(bw, "%s{cache=\"%s\"} %d\n", c.name, escapeLabel(s.name), c.value(s.stats))
+		}
+	}
+
+	writeHeader(bw, "inmemcache_entries", "Number of entries in the cache.", "gauge")
+	for _, s := range samples {
+		if s.hasLen {
+			fmt.Fprintf(bw, "inmemcache_entries{cache=\"%s\"} %d\n", escapeLabel(s.name), s.len)
+		}
+	}
+	writeHeader(bw, "inmemcache_weight", "Total weight of the entries in the cache.", "gauge")
+	for _, s := range samples {
+		if s.hasWeight {
+			fmt.Fprintf(bw, "inmemcache_weight{cache=\"%s\"} %d\n", escapeLabel(s.name), s.weight)
+		}
+	}
+
+	const histogram = "inmemcache_load_duration_seconds"
+	writeHeader(bw, histogram, "Time spent in loader calls, successful or not.", "histogram")
+	bounds := cache.LoadTimeBounds()
+	for _, s := range samples {
+		name := escapeLabel(s.name)
+		// Prometheus buckets are cumulative
+		var cumulative uint64
+		for i, bound := range bounds {
+			cumulative += s.stats.LoadTimeCounts[i]
+			le := strconv.FormatFloat(bound.Seconds(), 'g', -1, 64)
+			fmt.Fprintf(bw, "%s_bucket{cache=\"%s\",le=\"%s\"} %d\n", histogram, name, le, cumulative)
+		}
+		// Derived from the buckets rather than Loads, so that the +Inf bucket
+		// matches them even if the snapshot raced with a load
+		count := cumulative + s.stats.LoadTimeCounts[len(bounds)]
+		fmt.Fprintf(bw, "%s_bucket{cache=\"%s\",le=\"+Inf\"} %d\n", histogram, name, count)
+		fmt.Fprintf(bw, "%s_sum{cache=\"%s\"} %s\n", histogram, name, strconv.FormatFloat(s.stats.LoadTime.Seconds(), 'g', -1, 64))
+		fmt.Fprintf(bw, "%s_count{cache=\"%s\"} %d\n", histogram, name, count)
+	}
+
+	return bw.Flush()
+}
+
+func writeHeader(w io.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// labelEscaper escapes label values as the text format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
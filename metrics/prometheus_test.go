@@ -0,0 +1,108 @@
+package metrics_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/clock/clocktest"
+	"github.com/Varun0157/in-mem-cache/metrics"
+	"github.com/Varun0157/in-mem-cache/ttl"
+)
+
+// scrape fetches the registry's metrics through its HTTP handler.
+func scrape(t *testing.T, registry *metrics.Registry) string {
+	t.Helper()
+
+	server := httptest.NewServer(registry.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestHandler(t *testing.T) {
+	clk := clocktest.NewFake(time.Unix(0, 0))
+	users, err := cache.NewWithOptions(
+		cache.WithCapacity[string, string](2),
+		cache.WithClock[string, string](clk),
+		cache.WithStats[string, string](),
+	)
+	require.NoError(t, err)
+
+	users.Set("a", "alice")
+	users.Set("b", "bob")
+	users.Set("c", "carol") // Evicts "a"
+	users.Get("b")
+	users.Get("a")
+	users.GetOrLoad("d", func(string) (string, error) {
+		clk.Advance(3 * time.Millisecond)
+		return "", errors.New("unavailable")
+	})
+
+	sessions := ttl.NewCache(cache.New[string, int](10, nil), ttl.WithStats())
+	sessions.Get("x")
+
+	registry := metrics.NewRegistry()
+	require.NoError(t, registry.Register("users", users))
+	require.NoError(t, registry.Register(`odd "name"`, sessions))
+
+	body := scrape(t, registry)
+	for _, line := range []string{
+		"# TYPE inmemcache_hits_total counter",
+		`inmemcache_hits_total{cache="users"} 1`,
+		`inmemcache_misses_total{cache="users"} 2`,
+		`inmemcache_evictions_total{cache="users"} 1`,
+		`inmemcache_load_failures_total{cache="users"} 1`,
+		`inmemcache_misses_total{cache="odd \"name\""} 1`,
+		"# TYPE inmemcache_entries gauge",
+		`inmemcache_entries{cache="users"} 2`,
+		`inmemcache_weight{cache="users"} 2`,
+		"# TYPE inmemcache_load_duration_seconds histogram",
+		`inmemcache_load_duration_seconds_bucket{cache="users",le="0.001"} 0`,
+		`inmemcache_load_duration_seconds_bucket{cache="users",le="0.005"} 1`,
+		`inmemcache_load_duration_seconds_bucket{cache="users",le="+Inf"} 1`,
+		`inmemcache_load_duration_seconds_sum{cache="users"} 0.003`,
+		`inmemcache_load_duration_seconds_count{cache="users"} 1`,
+	} {
+		require.Contains(t, body, line+"\n")
+	}
+
+	// The ttl decorator has no size, so it gets no gauges
+	require.NotContains(t, body, `inmemcache_entries{cache="odd`)
+
+	// Caches are listed in name order, so the output is stable
+	require.Less(t,
+		strings.Index(body, `inmemcache_hits_total{cache="odd`),
+		strings.Index(body, `inmemcache_hits_total{cache="users"}`))
+}
+
+func TestRegistry_Register(t *testing.T) {
+	registry := metrics.NewRegistry()
+	c := cache.New[string, int](1, nil)
+
+	require.ErrorIs(t, registry.Register("", c), metrics.ErrEmptyName)
+	require.NoError(t, registry.Register("c", c))
+	require.ErrorIs(t, registry.Register("c", c), metrics.ErrDuplicateName)
+
+	require.True(t, registry.Unregister("c"))
+	require.False(t, registry.Unregister("c"))
+	require.NotContains(t, scrape(t, registry), `cache="c"`)
+
+	// The name can be reused once it is free again
+	require.NoError(t, registry.Register("c", c))
+}
@@ -0,0 +1,145 @@
+// Package metrics exports the statistics of named caches for monitoring
+// systems. It has no dependencies beyond the standard library.
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+)
+
+// Errors returned by Registry.Register.
+var (
+	ErrEmptyName     = errors.New("metrics: cache name must not be empty")
+	ErrDuplicateName = errors.New("metrics: cache name already registered")
+)
+
+// Source is anything that reports cache statistics, such as a *cache.Cache
+// or *ttl.Cache created with stats enabled. Sources that also have Len or
+// Weight methods, like *cache.Cache, get size and weight gauges as well.
+type Source interface {
+	Stats() cache.Stats
+}
+
+// Registry is a set of named Sources. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	sources map[string]*registration
+}
+
+// registration is a registered Source. Its identity tells a source apart
+// from a later one registered under the same name.
+type registration struct {
+	source Source
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{sources: make(map[string]*registration)}
+}
+
+// Default is the Registry used by the package-level functions.
+var Default = NewRegistry()
+
+// Register adds a source under name, which becomes the value of its cache
+// label. Names must be non-empty and unique within the registry.
+//
+// Sources with an OnClose method, like *cache.Cache and *ttl.Cache, are
+// unregistered automatically when they are closed.
+func (r *Registry) Register(name string, source Source) error {
+	if name == "" {
+		return ErrEmptyName
+	}
+
+	reg := &registration{source: source}
+	r.mu.Lock()
+	if _, dup := r.sources[name]; dup {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %q", ErrDuplicateName, name)
+	}
+	r.sources[name] = reg
+	r.mu.Unlock()
+
+	// Outside the lock, since a closed source runs the hook straight away.
+	if closer, ok := source.(interface{ OnClose(fn func()) }); ok {
+		closer.OnClose(func() {
+			r.remove(name, reg)
+		})
+	}
+	return nil
+}
+
+// remove unregisters name only if it is still held by reg, so that closing
+// a source does not unregister another one that has taken over its name.
+func (r *Registry) remove(name string, reg *registration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.sources[name] == reg {
+		delete(r.sources, name)
+	}
+}
+
+// Unregister removes the source registered under name. It reports whether
+// there was one.
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.sources[name]
+	delete(r.sources, name)
+	return ok
+}
+
+// Register adds a source to the Default registry.
+func Register(name string, source Source) error {
+	return Default.Register(name, source)
+}
+
+// Unregister removes a source from the Default registry.
+func Unregister(name string) bool {
+	return Default.Unregister(name)
+}
+
+// sample is the state of one source at the time it was scraped.
+type sample struct {
+	name      string
+	stats     cache.Stats
+	len       int
+	weight    int
+	hasLen    bool
+	hasWeight bool
+}
+
+// collect samples every registered source, in name order.
+func (r *Registry) collect() []sample {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.sources))
+	for name := range r.sources {
+		names = append(names, name)
+	}
+	sources := make([]Source, 0, len(names))
+	slices.Sort(names)
+	for _, name := range names {
+		sources = append(sources, r.sources[name].source)
+	}
+	r.mu.RUnlock()
+
+	// Sources are read without holding the registry lock, since reading
+	// them may take their own locks.
+	samples := make([]sample, len(sources))
+	for i, source := range sources {
+		s := sample{name: names[i], stats: source.Stats()}
+		if sized, ok := source.(interface{ Len() int }); ok {
+			s.len, s.hasLen = sized.Len(), true
+		}
+		if weighed, ok := source.(interface{ Weight() int }); ok {
+			s.weight, s.hasWeight = weighed.Weight(), true
+		}
+		samples[i] = s
+	}
+	return samples
+}
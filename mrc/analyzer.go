@@ -0,0 +1,182 @@
+// Package mrc estimates the miss-ratio curve of a cache from its access
+// stream, i.e. the miss ratio an LRU cache would have at every capacity, in
+// a single pass. It uses SHARDS (Waldspurger et al., FAST '15): only keys
+// whose hash falls below a threshold are tracked, and their reuse distances
+// are scaled up by the inverse of the sampling rate.
+package mrc
+
+import (
+	"cmp"
+	"hash/maphash"
+	"math"
+	"slices"
+	"sync"
+)
+
+// DefaultSampleRate is the fraction of keys tracked when no other rate is
+// given. SHARDS is typically accurate to within a few percent at 1%.
+const DefaultSampleRate = 0.01
+
+// Point is the estimated miss ratio of an LRU cache of a given capacity.
+type Point struct {
+	Capacity  int
+	MissRatio float64
+}
+
+// Analyzer estimates an LRU miss-ratio curve from a stream of key accesses.
+// Its memory grows with the number of distinct sampled keys, i.e. roughly
+// the number of distinct keys times the sampling rate. It is safe for
+// concurrent use.
+type Analyzer[K comparable] struct {
+	hasher    func(K) uint64
+	rate      float64
+	threshold uint64 // Keys hashing below this are sampled
+
+	mu        sync.Mutex
+	accesses  uint64    // Every access, sampled or not
+	sampled   uint64    // Sampled accesses
+	last      map[K]int // Time of the latest access to each sampled key
+	live      *fenwick  // 1 at the latest access time of every key
+	now       int       // Time of the next sampled access
+	distances []uint64  // Histogram of unscaled reuse distances
+}
+
+// NewAnalyzer creates an Analyzer that samples the given fraction of keys.
+// Rates outside (0, 1] mean DefaultSampleRate; a rate of 1 tracks every key
+// and gives the exact LRU curve. Keys are sampled by hasher, which should
+// spread them uniformly over the uint64 range; if hasher is nil, keys are
+// hashed with maphash.Comparable and a random seed.
+func NewAnalyzer[K comparable](rate float64, hasher func(K) uint64) *Analyzer[K] {
+	if !(rate > 0 && rate <= 1) {
+		rate = DefaultSampleRate
+	}
+	if hasher == nil {
+		seed := maphash.MakeSeed()
+		hasher = func(key K) uint64 {
+			return maphash.Comparable(seed, key)
+		}
+	}
+	threshold := uint64(math.MaxUint64)
+	if rate < 1 {
+		threshold = uint64(rate * (1 << 63) * 2)
+	}
+	return &Analyzer[K]{
+		hasher:    hasher,
+		rate:      rate,
+		threshold: threshold,
+		last:      make(map[K]int),
+		live:      newFenwick(1024),
+	}
+}
+
+// SampleRate returns the fraction of keys the analyzer tracks.
+func (a *Analyzer[K]) SampleRate() float64 {
+	return a.rate
+}
+
+// Record adds an access to key to the stream.
+func (a *Analyzer[K]) Record(key K) {
+	sampled := a.rate == 1 || a.hasher(key) < a.threshold
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.accesses++
+	if !sampled {
+		return
+	}
+	a.sampled++
+
+	if a.now == a.live.len() {
+		a.compactLocked()
+	}
+
+	if prev, ok := a.last[key]; ok {
+		// The reuse distance is the number of distinct keys accessed since
+		// the previous access to this one.
+		distance := a.live.prefix(a.now-1) - a.live.prefix(prev)
+		if distance >= len(a.distances) {
+			a.distances = append(a.distances, make([]uint64, distance-len(a.distances)+1)...)
+		}
+		a.distances[distance]++
+		a.live.add(prev, -1)
+	}
+	a.last[key] = a.now
+	a.live.add(a.now, 1)
+	a.now++
+}
+
+// compactLocked renumbers the latest access times of the tracked keys to
+// 0..n-1, keeping their order, so that time fits in the tree again. The tree
+// is grown if more than half of it would still be in use. The caller must
+// hold a.mu.
+func (a *Analyzer[K]) compactLocked() {
+	type access struct {
+		key  K
+		time int
+	}
+	accesses := make([]access, 0, len(a.last))
+	for key, time := range a.last {
+		accesses = append(accesses, access{key, time})
+	}
+	slices.SortFunc(accesses, func(x, y access) int {
+		return cmp.Compare(x.time, y.time)
+	})
+	for i, acc := range accesses {
+		a.last[acc.key] = i
+	}
+
+	size := a.live.len()
+	if 2*len(accesses) > size {
+		size *= 2
+	}
+	a.live.fill(size, len(accesses))
+	a.now = len(accesses)
+}
+
+// Accesses returns the number of accesses recorded, sampled or not.
+func (a *Analyzer[K]) Accesses() uint64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.accesses
+}
+
+// Curve returns the estimated miss ratio of an LRU cache at each of the
+// given capacities. With no accesses recorded, every miss ratio is 0.
+func (a *Analyzer[K]) Curve(capacities []int) []Point {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// SHARDS-adj: the number of sampled accesses deviates from its
+	// expectation when a few hot keys happen to be sampled or not. The
+	// difference is credited to the smallest distance, which corrects
+	// most of the resulting bias.
+	expected := float64(a.accesses) * a.rate
+	adjustment := expected - float64(a.sampled)
+	total := float64(a.sampled) + adjustment
+
+	points := make([]Point, len(capacities))
+	for i, capacity := range capacities {
+		points[i].Capacity = capacity
+		if total <= 0 {
+			continue
+		}
+
+		// An access hits in an LRU cache of capacity c if fewer than c other
+		// keys were accessed since, i.e. if its scaled distance is below c.
+		hits := 0.0
+		if capacity > 0 {
+			hits = adjustment
+			limit := float64(capacity) * a.rate
+			for d, count := range a.distances {
+				if float64(d) >= limit {
+					break
+				}
+				hits += float64(count)
+			}
+		}
+		points[i].MissRatio = min(max(1-hits/total, 0), 1)
+	}
+	return points
+}
@@ -0,0 +1,118 @@
+package mrc_test
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/policies"
+	"github.com/Varun0157/in-mem-cache/mrc"
+)
+
+// zipfTrace returns a skewed access stream over keys.
+func zipfTrace(n int, keys uint64) []uint64 {
+	zipf := rand.NewZipf(rand.New(rand.NewPCG(1, 2)), 1.1, 1, keys-1)
+	trace := make([]uint64, n)
+	for i := range trace {
+		trace[i] = zipf.Uint64()
+	}
+	return trace
+}
+
+// splitmix is a fixed hash, so that the sampled keys are the same on every
+// run.
+func splitmix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// exactMissRatio simulates an LRU cache of the given capacity over a trace,
+// filling it on every miss. It drives the LRU policy directly, so that the
+// reference does not depend on the cache it is used to size.
+func exactMissRatio(trace []uint64, capacity int) float64 {
+	policy := policies.NewLRU[uint64]()
+	resident := make(map[uint64]bool, capacity)
+	misses := 0
+	for _, key := range trace {
+		if resident[key] {
+			policy.OnAccess(key)
+			continue
+		}
+		misses++
+		if len(resident) == capacity {
+			delete(resident, policy.OnEvict())
+		}
+		resident[key] = true
+		policy.OnAdd(key)
+	}
+	return float64(misses) / float64(len(trace))
+}
+
+func TestAnalyzer_ExactAtFullRate(t *testing.T) {
+	trace := zipfTrace(50_000, 5_000)
+	analyzer := mrc.NewAnalyzer[uint64](1, nil)
+	for _, key := range trace {
+		analyzer.Record(key)
+	}
+
+	capacities := []int{1, 10, 100, 500, 1000, 5000}
+	for _, p := range analyzer.Curve(capacities) {
+		require.InDelta(t, exactMissRatio(trace, p.Capacity), p.MissRatio, 1e-9, "capacity %d", p.Capacity)
+	}
+}
+
+func TestAnalyzer_SampledEstimate(t *testing.T) {
+	trace := zipfTrace(200_000, 20_000)
+	analyzer := mrc.NewAnalyzer(0.1, splitmix)
+	for _, key := range trace {
+		analyzer.Record(key)
+	}
+	require.Equal(t, uint64(len(trace)), analyzer.Accesses())
+
+	// Sampling a tenth of the keys stays close to the exact curve
+	for _, p := range analyzer.Curve([]int{500, 2000, 8000}) {
+		require.InDelta(t, exactMissRatio(trace, p.Capacity), p.MissRatio, 0.05, "capacity %d", p.Capacity)
+	}
+}
+
+func TestAnalyzer_Empty(t *testing.T) {
+	analyzer := mrc.NewAnalyzer[string](0, nil) // Falls back to the default rate
+	require.Equal(t, mrc.DefaultSampleRate, analyzer.SampleRate())
+	require.Equal(t, []mrc.Point{{Capacity: 10}}, analyzer.Curve([]int{10}))
+}
+
+func TestRecorder(t *testing.T) {
+	analyzer := mrc.NewAnalyzer[string](1, nil)
+	recorder := mrc.NewRecorder(cache.New[string, int](10, nil), analyzer)
+
+	recorder.Get("a") // A miss, filled by the caller
+	recorder.Set("a", 1)
+	value, found := recorder.Get("a")
+	require.True(t, found)
+	require.Equal(t, 1, value)
+
+	require.Equal(t, uint64(2), analyzer.Accesses())
+	require.Equal(t, []mrc.Point{{Capacity: 1, MissRatio: 0.5}}, analyzer.Curve([]int{1}))
+}
+
+func TestWriteCSVAndTable(t *testing.T) {
+	points := []mrc.Point{{Capacity: 100, MissRatio: 0.5}, {Capacity: 1000, MissRatio: 0.125}}
+
+	var buf bytes.Buffer
+	require.NoError(t, mrc.WriteCSV(&buf, points))
+	require.Equal(t, "capacity,miss_ratio\n100,0.500000\n1000,0.125000\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, mrc.WriteTable(&buf, points))
+	require.Equal(t, ""+
+		"  capacity  miss ratio\n"+
+		"       100      50.00%\n"+
+		"      1000      12.50%\n", buf.String())
+
+	require.Equal(t, []int{250, 500, 750, 1000}, mrc.Capacities(1000, 4))
+}
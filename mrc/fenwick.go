@@ -0,0 +1,51 @@
+package mrc
+
+// fenwick is a binary indexed tree over a fixed number of slots, supporting
+// point updates and prefix sums in O(log n).
+type fenwick struct {
+	tree []int // 1-based; tree[0] is unused
+}
+
+func newFenwick(n int) *fenwick {
+	return &fenwick{tree: make([]int, n+1)}
+}
+
+// len returns the number of slots.
+func (f *fenwick) len() int {
+	return len(f.tree) - 1
+}
+
+// add adds delta to slot i.
+func (f *fenwick) add(i, delta int) {
+	for i++; i < len(f.tree); i += i & -i {
+		f.tree[i] += delta
+	}
+}
+
+// prefix returns the sum of slots 0 through i inclusive. A negative i
+// yields 0.
+func (f *fenwick) prefix(i int) int {
+	sum := 0
+	for i++; i > 0; i -= i & -i {
+		sum += f.tree[i]
+	}
+	return sum
+}
+
+// fill resets the tree to n slots, the first ones of which hold 1, in O(n).
+func (f *fenwick) fill(n, ones int) {
+	if cap(f.tree) >= n+1 {
+		f.tree = f.tree[:n+1]
+		clear(f.tree)
+	} else {
+		f.tree = make([]int, n+1)
+	}
+	for i := 1; i <= n; i++ {
+		if i <= ones {
+			f.tree[i]++
+		}
+		if parent := i + i&-i; parent <= n {
+			f.tree[parent] += f.tree[i]
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package mrc
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFenwick_MatchesNaiveSums(t *testing.T) {
+	const n = 100
+	f := newFenwick(n)
+	naive := make([]int, n)
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for range 1000 {
+		i, delta := rng.IntN(n), rng.IntN(5)-2
+		f.add(i, delta)
+		naive[i] += delta
+
+		j := rng.IntN(n+1) - 1
+		sum := 0
+		for _, v := range naive[:j+1] {
+			sum += v
+		}
+		require.Equal(t, sum, f.prefix(j))
+	}
+}
+
+func TestFenwick_Fill(t *testing.T) {
+	f := newFenwick(4)
+	f.add(3, 7)
+
+	// Refilling discards the old contents and may grow the tree
+	f.fill(37, 20)
+	require.Equal(t, 37, f.len())
+	for i := range 37 {
+		require.Equal(t, min(i+1, 20), f.prefix(i), "prefix(%d)", i)
+	}
+}
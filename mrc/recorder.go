@@ -0,0 +1,41 @@
+package mrc
+
+import "github.com/Varun0157/in-mem-cache/cache"
+
+// Recorder is a decorator that feeds every lookup made through it to an
+// Analyzer, to estimate the curve of a live cache.
+type Recorder[K comparable, V any] struct {
+	coreCache cache.Cacheable[K, V]
+	analyzer  *Analyzer[K]
+}
+
+// NewRecorder creates a Recorder that wraps core and records its lookups
+// in analyzer.
+func NewRecorder[K comparable, V any](core cache.Cacheable[K, V], analyzer *Analyzer[K]) *Recorder[K, V] {
+	return &Recorder[K, V]{coreCache: core, analyzer: analyzer}
+}
+
+// Get records an access to key and retrieves its value from the core cache.
+func (r *Recorder[K, V]) Get(key K) (V, bool) {
+	r.analyzer.Record(key)
+	return r.coreCache.Get(key)
+}
+
+// Set adds or updates a value in the core cache. Writes are not recorded:
+// a miss followed by a Set is a single access.
+func (r *Recorder[K, V]) Set(key K, value V) {
+	r.coreCache.Set(key, value)
+}
+
+// Delete removes a key from the core cache.
+func (r *Recorder[K, V]) Delete(key K) {
+	r.coreCache.Delete(key)
+}
+
+// AddRemovalListener registers a listener with the core cache.
+func (r *Recorder[K, V]) AddRemovalListener(listener cache.RemovalListener[K, V]) {
+	r.coreCache.AddRemovalListener(listener)
+}
+
+// Static assertion to ensure *mrc.Recorder satisfies the cache.Cacheable interface.
+var _ cache.Cacheable[any, any] = (*Recorder[any, any])(nil)
@@ -0,0 +1,47 @@
+package mrc
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// Capacities returns n capacities evenly spaced up to and including
+// maxCapacity, for use with Analyzer.Curve.
+func Capacities(maxCapacity, n int) []int {
+	if maxCapacity <= 0 || n <= 0 {
+		return nil
+	}
+	n = min(n, maxCapacity)
+	capacities := make([]int, n)
+	for i := range capacities {
+		capacities[i] = maxCapacity * (i + 1) / n
+	}
+	return capacities
+}
+
+// WriteCSV writes a curve as CSV, with a capacity,miss_ratio header.
+func WriteCSV(w io.Writer, points []Point) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"capacity", "miss_ratio"})
+	for _, p := range points {
+		cw.Write([]string{
+			strconv.Itoa(p.Capacity),
+			strconv.FormatFloat(p.MissRatio, 'f', 6, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteTable writes a curve as an aligned, human-readable table.
+func WriteTable(w io.Writer, points []Point) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "capacity\tmiss ratio\t")
+	for _, p := range points {
+		fmt.Fprintf(tw, "%d\t%.2f%%\t\n", p.Capacity, 100*p.MissRatio)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,151 @@
+package ttl
+
+import (
+	"time"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+)
+
+// Item is a key-value pair with its own expiration, for SetManyWithTTL.
+type Item[K comparable, V any] struct {
+	Key   K
+	Value V
+	TTL   time.Duration // Absolute lifetime; zero or negative for none
+	Idle  time.Duration // Idle timeout; zero or negative for none
+}
+
+// GetMany retrieves several values under a single lock acquisition, treating
+// each key as Get would. Keys that are missing or expired are absent from the
+// result.
+func (c *Cache[K, V]) GetMany(keys []K) map[K]V {
+	c.mu.RLock()
+	now := c.clock.Now()
+	needsWrite := false
+	for _, key := range keys {
+		expiresAt, hasExpiration := c.expirations.Deadline(key)
+		if hasExpiration && (now.After(expiresAt) || c.expiries[key].idle > 0) {
+			needsWrite = true
+			break
+		}
+	}
+	if !needsWrite {
+		// Nothing expired or slides, so the read lock is enough.
+		found := c.coreGetMany(keys)
+		c.mu.RUnlock()
+		c.recordGetMany(keys, found)
+		return found
+	}
+	c.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.dispatch()
+	defer c.mu.Unlock()
+
+	now = c.clock.Now()
+	c.reconcileLocked()
+	live := make([]K, 0, len(keys))
+	for _, key := range keys {
+		if !c.removeIfExpiredLocked(key, now) {
+			live = append(live, key)
+		}
+	}
+
+	found := c.coreGetMany(live)
+	for key := range found {
+		c.touchLocked(key, now)
+	}
+	c.recordGetMany(keys, found)
+	return found
+}
+
+// SetMany adds several values with no expiration under a single lock
+// acquisition.
+func (c *Cache[K, V]) SetMany(items map[K]V) {
+	batch := make([]Item[K, V], 0, len(items))
+	for key, value := range items {
+		batch = append(batch, Item[K, V]{Key: key, Value: value})
+	}
+	c.SetManyWithTTL(batch)
+}
+
+// SetManyWithTTL adds several values, each with its own TTL and idle timeout,
+// under a single lock acquisition. If a key appears more than once, the last
+// item wins.
+func (c *Cache[K, V]) SetManyWithTTL(items []Item[K, V]) {
+	c.mu.Lock()
+	defer c.dispatch()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	c.reconcileLocked()
+	values := make(map[K]V, len(items))
+	for _, item := range items {
+		values[item.Key] = item.Value
+
+		var exp expiry
+		if item.TTL > 0 {
+			exp.expiresAt = now.Add(item.TTL)
+		}
+		if item.Idle > 0 {
+			exp.idle = item.Idle
+		}
+		c.scheduleLocked(item.Key, exp, now)
+	}
+
+	for key := range values {
+		c.recordWriteLocked(key)
+	}
+
+	// Schedule first: the batch may evict some of its own items, and
+	// reconciling afterwards drops their expirations again.
+	c.coreSetMany(values)
+	c.reconcileLocked()
+	c.deleteExpiredLocked(now)
+}
+
+// recordGetMany records the outcome of a batch lookup of keys, which found
+// the entries in found. Every key asked for counts, as it would with Get,
+// even if it was repeated.
+func (c *Cache[K, V]) recordGetMany(keys []K, found map[K]V) {
+	if c.stats == nil {
+		return
+	}
+	hits := 0
+	for _, key := range keys {
+		if _, ok := found[key]; ok {
+			hits++
+		}
+	}
+	c.stats.RecordHits(hits)
+	c.stats.RecordMisses(len(keys) - hits)
+}
+
+// coreGetMany reads several keys from the core cache, in one call if it
+// supports batching.
+func (c *Cache[K, V]) coreGetMany(keys []K) map[K]V {
+	if core, ok := c.coreCache.(cache.Batcher[K, V]); ok {
+		return core.GetMany(keys)
+	}
+	found := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if value, ok := c.coreCache.Get(key); ok {
+			found[key] = value
+		}
+	}
+	return found
+}
+
+// coreSetMany writes several entries to the core cache, in one call if it
+// supports batching.
+func (c *Cache[K, V]) coreSetMany(items map[K]V) {
+	if core, ok := c.coreCache.(cache.Batcher[K, V]); ok {
+		core.SetMany(items)
+		return
+	}
+	for key, value := range items {
+		c.coreCache.Set(key, value)
+	}
+}
+
+// Static assertion to ensure *ttl.Cache satisfies the cache.Batcher interface.
+var _ cache.Batcher[any, any] = (*Cache[any, any])(nil)
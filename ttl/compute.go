@@ -0,0 +1,98 @@
+package ttl
+
+import (
+	"time"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+)
+
+// Compute atomically reads, modifies and writes the entry for key, treating
+// an expired entry as absent. An entry that already exists keeps its
+// expiration; one created by Compute never expires. See cache.Cache.Compute
+// for the semantics of fn and the results.
+//
+// The cache stays locked while fn runs, so fn must not call back into it.
+func (c *Cache[K, V]) Compute(key K, fn func(old V, exists bool) (V, cache.Op)) (V, bool) {
+	return c.ComputeWithTTL(key, 0, fn)
+}
+
+// ComputeWithTTL is like Compute, except that an entry it creates expires
+// after ttl. An entry that already exists keeps its expiration, which makes
+// it suitable for fixed-window counters.
+func (c *Cache[K, V]) ComputeWithTTL(key K, ttl time.Duration, fn func(old V, exists bool) (V, cache.Op)) (V, bool) {
+	c.mu.Lock()
+	defer c.dispatch()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	c.reconcileLocked()
+	c.removeIfExpiredLocked(key, now)
+
+	created := false
+	value, exists := c.coreCompute(key, func(old V, exists bool) (V, cache.Op) {
+		value, op := fn(old, exists)
+		created = !exists && op == cache.OpStore
+		if created {
+			c.stats.RecordSet()
+		} else if op == cache.OpStore {
+			c.stats.RecordUpdate()
+		}
+		return value, op
+	})
+	c.reconcileLocked()
+
+	switch {
+	case !exists:
+		c.forgetLocked(key)
+	case created && ttl > 0:
+		c.scheduleLocked(key, expiry{expiresAt: now.Add(ttl)}, now)
+	default:
+		c.touchLocked(key, now)
+	}
+	return value, exists
+}
+
+// SetIfAbsent stores value with no expiration, but only if key is not
+// already in the cache. It returns the value now held for key, and whether
+// it was already present.
+func (c *Cache[K, V]) SetIfAbsent(key K, value V) (actual V, loaded bool) {
+	return cache.SetIfAbsent(c, key, value)
+}
+
+// CompareAndSwap replaces the value for key with new if its current value
+// equals old, keeping its expiration. See cache.Cache.CompareAndSwap.
+func (c *Cache[K, V]) CompareAndSwap(key K, old, new V, equal func(a, b V) bool) bool {
+	return cache.CompareAndSwap(c, key, old, new, equal)
+}
+
+// Update atomically replaces the value for key with fn applied to it, if the
+// key exists, keeping its expiration. See cache.Cache.Update.
+func (c *Cache[K, V]) Update(key K, fn func(old V) V) (V, bool) {
+	return cache.Update(c, key, fn)
+}
+
+// coreCompute runs fn against the core cache, atomically if the core
+// supports it. Otherwise it is only atomic with respect to other users of
+// this decorator, which hold c.mu. The caller must hold c.mu for writing.
+func (c *Cache[K, V]) coreCompute(key K, fn func(old V, exists bool) (V, cache.Op)) (V, bool) {
+	if core, ok := c.coreCache.(cache.Computer[K, V]); ok {
+		return core.Compute(key, fn)
+	}
+
+	old, exists := c.coreCache.Get(key)
+	value, op := fn(old, exists)
+	switch op {
+	case cache.OpStore:
+		c.coreCache.Set(key, value)
+		return value, true
+	case cache.OpDelete:
+		c.coreCache.Delete(key)
+		var zeroV V
+		return zeroV, false
+	default:
+		return old, exists
+	}
+}
+
+// Static assertion to ensure *ttl.Cache satisfies the cache.Computer interface.
+var _ cache.Computer[any, any] = (*Cache[any, any])(nil)
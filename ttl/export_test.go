@@ -0,0 +1,17 @@
+package ttl
+
+// TrackedLen returns the number of keys whose expiration is being tracked.
+func (c *Cache[K, V]) TrackedLen() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.expiries)
+}
+
+// ScheduledLen returns the number of keys scheduled in the expiration wheel.
+func (c *Cache[K, V]) ScheduledLen() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.expirations.Len()
+}
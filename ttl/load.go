@@ -0,0 +1,54 @@
+package ttl
+
+import (
+	"time"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+)
+
+// GetOrLoad returns the value for key, calling loader to produce it if the
+// key is missing or expired. A loaded value never expires. See
+// GetOrLoadWithTTL.
+func (c *Cache[K, V]) GetOrLoad(key K, loader func(key K) (V, error)) (V, error) {
+	return c.GetOrLoadWithTTL(key, 0, loader)
+}
+
+// GetOrLoadWithTTL returns the value for key, calling loader to produce it
+// if the key is missing or expired. A loaded value is stored with the given
+// ttl unless another one was stored for key in the meantime, in which case
+// that one is returned instead. If loader fails, nothing is stored and its
+// error is returned.
+//
+// loader runs without the cache locked, so it may take its time, but
+// concurrent misses for the same key may each call it.
+func (c *Cache[K, V]) GetOrLoadWithTTL(key K, ttl time.Duration, loader func(key K) (V, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	span := c.startSpan(cache.SpanLoad, key)
+	start := c.clock.Now()
+	value, err := loader(key)
+	elapsed := c.clock.Now().Sub(start)
+	if span != nil {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}
+	if err != nil {
+		c.stats.RecordLoadFailure(elapsed)
+		c.debug("cache load failed", "key", key, "error", err, "duration", elapsed)
+		var zeroV V
+		return zeroV, err
+	}
+	c.stats.RecordLoadSuccess(elapsed)
+
+	actual, _ := c.ComputeWithTTL(key, ttl, func(old V, exists bool) (V, cache.Op) {
+		if exists {
+			return old, cache.OpKeep
+		}
+		return value, cache.OpStore
+	})
+	return actual, nil
+}
@@ -0,0 +1,15 @@
+package ttl_test
+
+import (
+	"testing"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/cachetest"
+	"github.com/Varun0157/in-mem-cache/ttl"
+)
+
+func BenchmarkTTLCacheMatrix(b *testing.B) {
+	cachetest.RunMatrix(b, func(policy cache.EvictionPolicy[uint64], capacity int) cache.Cacheable[uint64, uint64] {
+		return ttl.NewCache(cache.New[uint64, uint64](capacity, policy))
+	})
+}
@@ -0,0 +1,75 @@
+package ttl
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/clock"
+)
+
+// Option configures a Cache created by NewCache.
+type Option func(*config)
+
+// config holds the settings collected from Options.
+type config struct {
+	clock           clock.Clock
+	cleanupInterval time.Duration
+	stats           *cache.StatsCounter
+	logger          *slog.Logger
+	tracer          cache.Tracer
+}
+
+// defaultConfig returns the settings used when no Options are given.
+func defaultConfig() config {
+	return config{
+		clock: clock.Real(),
+	}
+}
+
+// WithClock sets the source of time used for deadlines and cleanup.
+// It defaults to the system clock; tests can pass a clocktest.Fake.
+func WithClock(clk clock.Clock) Option {
+	return func(cfg *config) {
+		if clk != nil {
+			cfg.clock = clk
+		}
+	}
+}
+
+// WithCleanupInterval starts a background sweep that removes expired keys
+// every interval, even if the cache sees no writes. The sweep runs until
+// Close is called. A non-positive interval disables it, which is the default.
+func WithCleanupInterval(interval time.Duration) Option {
+	return func(cfg *config) {
+		cfg.cleanupInterval = interval
+	}
+}
+
+// WithStats enables the statistics returned by Stats. They describe the
+// cache as seen through the decorator: an expired entry is a miss, and its
+// removal an expiration. They are off by default.
+func WithStats() Option {
+	return func(cfg *config) {
+		cfg.stats = cache.NewStatsCounter()
+	}
+}
+
+// WithLogger makes the cache log debug-level events, such as expirations and
+// loader failures, to logger. Evictions are logged by the core cache, if it
+// has a logger of its own. Use logger.With to attach attributes such as the
+// cache's name. A nil logger, the default, turns logging off.
+func WithLogger(logger *slog.Logger) Option {
+	return func(cfg *config) {
+		cfg.logger = logger
+	}
+}
+
+// WithTracer makes the cache start a span through tracer around every Get,
+// Set, Delete and loader call. Get spans report whether the key had expired.
+// Tracing is off by default.
+func WithTracer(tracer cache.Tracer) Option {
+	return func(cfg *config) {
+		cfg.tracer = tracer
+	}
+}
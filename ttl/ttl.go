@@ -1,15 +1,34 @@
 package ttl
 
 import (
+	"context"
+	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/clock"
+	"github.com/Varun0157/in-mem-cache/internal/scheduler"
 )
 
-// ttlEntry stores the expiration time for a key.
-type ttlEntry struct {
-	expiresAt time.Time
+// expiry describes when a key expires. A key may have an absolute deadline,
+// a sliding idle timeout, or both, in which case the earlier deadline wins.
+type expiry struct {
+	expiresAt time.Time     // Absolute deadline; zero if there is none
+	idle      time.Duration // Sliding timeout; zero if there is none
+	accessed  time.Time     // Last access, from which the idle timeout runs
+}
+
+// deadline returns the effective deadline for a key last accessed at lastAccess.
+func (e expiry) deadline(lastAccess time.Time) time.Time {
+	if e.idle <= 0 {
+		return e.expiresAt
+	}
+	idleDeadline := lastAccess.Add(e.idle)
+	if !e.expiresAt.IsZero() && e.expiresAt.Before(idleDeadline) {
+		return e.expiresAt
+	}
+	return idleDeadline
 }
 
 // Cache is a decorator that adds TTL (Time-To-Live) functionality
@@ -18,32 +37,103 @@ type Cache[K comparable, V any] struct {
 	// The underlying cache to store the actual key-value pairs.
 	coreCache cache.Cacheable[K, V]
 
+	clock           clock.Clock
+	cleanupInterval time.Duration
+	stats           *cache.StatsCounter // nil when stats are disabled
+	logger          *slog.Logger        // nil when logging is off
+	tracer          cache.Tracer        // nil when tracing is off
+
 	mu          sync.RWMutex
-	expirations map[K]ttlEntry // Stores only the expiration data
+	expiries    map[K]expiry        // How each expiring key expires
+	expirations *scheduler.Wheel[K] // When each expiring key is next due
+	janitor     clock.Timer         // Drives the periodic cleanup, if enabled
+	closed      bool
+	closeHooks  []func()
+
+	// Removals reported by the core cache are queued here rather than
+	// handled in its listener, since the listener runs while c.mu may
+	// already be held by the operation that caused the removal.
+	notifyMu  sync.Mutex
+	pending   []removal[K, V] // Reported by the core, not yet reconciled
+	outbox    []removal[K, V] // Reconciled, not yet passed to listeners
+	expiring  map[K]struct{}  // Keys being deleted because they expired
+	listeners []cache.RemovalListener[K, V]
+}
+
+// removal records an entry that left the core cache.
+type removal[K comparable, V any] struct {
+	key    K
+	value  V
+	reason cache.RemovalReason
 }
 
 // NewCache creates a new TTL-enabled cache decorator.
 // It wraps a core cache instance (like the one from your 'cache' package).
-func NewCache[K comparable, V any](core cache.Cacheable[K, V]) *Cache[K, V] {
-	return &Cache[K, V]{
-		coreCache:   core,
-		expirations: make(map[K]ttlEntry),
+func NewCache[K comparable, V any](core cache.Cacheable[K, V], opts ...Option) *Cache[K, V] {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	c := &Cache[K, V]{
+		coreCache:       core,
+		clock:           cfg.clock,
+		cleanupInterval: cfg.cleanupInterval,
+		stats:           cfg.stats,
+		logger:          cfg.logger,
+		tracer:          cfg.tracer,
+		expiries:        make(map[K]expiry),
+		expirations:     scheduler.New[K](scheduler.DefaultTick, cfg.clock.Now()),
+		expiring:        make(map[K]struct{}),
+	}
+	core.AddRemovalListener(c.onCoreRemoval)
+	if c.cleanupInterval > 0 {
+		c.mu.Lock()
+		c.janitor = c.clock.AfterFunc(c.cleanupInterval, c.cleanup)
+		c.mu.Unlock()
 	}
+	return c
 }
 
 // SetWithTTL adds a key-value pair to the cache with a specific TTL.
+// Every write also removes any other keys that have expired in the meantime.
 func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
-	c.coreCache.Set(key, value) // Set the value in the core cache
+	c.SetWithTTLAndIdleTimeout(key, value, ttl, 0)
+}
+
+// SetWithIdleTimeout adds a key-value pair that expires once it has not been
+// read for the given duration. Every successful Get moves the deadline forward.
+func (c *Cache[K, V]) SetWithIdleTimeout(key K, value V, idle time.Duration) {
+	c.SetWithTTLAndIdleTimeout(key, value, 0, idle)
+}
+
+// SetWithTTLAndIdleTimeout adds a key-value pair that expires after ttl, or
+// once it has not been read for idle, whichever comes first. A zero or
+// negative duration disables the corresponding limit.
+func (c *Cache[K, V]) SetWithTTLAndIdleTimeout(key K, value V, ttl, idle time.Duration) {
+	if span := c.startSpan(cache.SpanSet, key); span != nil {
+		defer span.End()
+	}
 
 	c.mu.Lock()
+	defer c.dispatch()
 	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	c.reconcileLocked()
+	c.recordWriteLocked(key)
+	c.coreCache.Set(key, value) // Set the value in the core cache
+	c.reconcileLocked()
+
+	var exp expiry
 	if ttl > 0 {
-		c.expirations[key] = ttlEntry{expiresAt: time.Now().Add(ttl)}
-	} else {
-		// If TTL is zero or negative, it means no expiration.
-		// We can remove it from our tracking map.
-		delete(c.expirations, key)
+		exp.expiresAt = now.Add(ttl)
 	}
+	if idle > 0 {
+		exp.idle = idle
+	}
+	c.scheduleLocked(key, exp, now)
+	c.deleteExpiredLocked(now)
 }
 
 // Set is required to satisfy the cache.Cacheable interface.
@@ -52,31 +142,411 @@ func (c *Cache[K, V]) Set(key K, value V) {
 	c.SetWithTTL(key, value, 0)
 }
 
-// Get retrieves a value. It first checks for expiration.
+// Get retrieves a value. It first checks for expiration, and moves the
+// deadline of keys with an idle timeout forward on a hit.
 func (c *Cache[K, V]) Get(key K) (V, bool) {
+	if span := c.startSpan(cache.SpanGet, key); span != nil {
+		value, found, expired := c.get(key)
+		span.SetAttributes(slog.Bool(cache.AttrHit, found), slog.Bool(cache.AttrExpired, expired))
+		span.End()
+		return value, found
+	}
+	value, found, _ := c.get(key)
+	return value, found
+}
+
+// get is Get without tracing. It also reports whether the key was found to
+// have expired.
+func (c *Cache[K, V]) get(key K) (value V, found, expired bool) {
 	c.mu.RLock()
-	entry, hasExpiration := c.expirations[key]
+	now := c.clock.Now()
+	expiresAt, hasExpiration := c.expirations.Deadline(key)
+
+	// Keys that neither expired nor slide can be served under the read lock.
+	if !hasExpiration || (!now.After(expiresAt) && c.expiries[key].idle <= 0) {
+		value, found = c.coreCache.Get(key)
+		c.mu.RUnlock()
+		c.recordGet(found)
+		return value, found, false
+	}
 	c.mu.RUnlock()
 
-	// Check if the item has an expiration time and if it has passed.
-	if hasExpiration && time.Now().After(entry.expiresAt) {
-		// Item has expired. Delete it from both caches.
-		c.Delete(key)
+	c.mu.Lock()
+	defer c.dispatch()
+	defer c.mu.Unlock()
+
+	// The key may have been given a new deadline while the lock was released.
+	now = c.clock.Now()
+	c.reconcileLocked()
+	if c.removeIfExpiredLocked(key, now) {
+		c.stats.RecordMisses(1)
 		var zeroV V
-		return zeroV, false
+		return zeroV, false, true
 	}
 
-	// If not expired (or no expiration was set), get it from the core cache.
-	return c.coreCache.Get(key)
+	value, found = c.coreCache.Get(key)
+	if found {
+		c.touchLocked(key, now)
+	}
+	c.recordGet(found)
+	return value, found, false
 }
 
 // Delete removes a key from both the TTL tracker and the core cache.
 func (c *Cache[K, V]) Delete(key K) {
+	if span := c.startSpan(cache.SpanDelete, key); span != nil {
+		defer span.End()
+	}
+
+	c.mu.Lock()
+	defer c.dispatch()
+	defer c.mu.Unlock()
+
+	c.reconcileLocked()
+	c.forgetLocked(key)
+	c.coreCache.Delete(key)
+	c.reconcileLocked()
+}
+
+// AddRemovalListener registers a listener that is told about every entry
+// that leaves the cache. Keys removed because their time ran out are
+// reported with cache.Expired; removals made directly on the core cache are
+// passed through with their original reason.
+func (c *Cache[K, V]) AddRemovalListener(listener cache.RemovalListener[K, V]) {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+
+	c.listeners = append(c.listeners, listener)
+}
+
+// TTL returns how long a key has left to live. ok is false if the key does
+// not exist, has expired, or never expires.
+func (c *Cache[K, V]) TTL(key K) (remaining time.Duration, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.clock.Now()
+	expiresAt, hasExpiration := c.expirations.Deadline(key)
+	if !hasExpiration || now.After(expiresAt) {
+		return 0, false
+	}
+	return expiresAt.Sub(now), true
+}
+
+// Expire sets a key to expire after d, without touching its value. A zero or
+// negative d deletes the key immediately. It reports whether the key existed.
+// If the core cache does not implement cache.Peeker, a key with no
+// expiration cannot be checked, and is assumed to exist.
+func (c *Cache[K, V]) Expire(key K, d time.Duration) bool {
+	c.mu.Lock()
+	defer c.dispatch()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	c.reconcileLocked()
+	return c.expireAtLocked(key, now.Add(d), now)
+}
+
+// ExpireAt sets a key to expire at t, without touching its value. A t that is
+// not in the future deletes the key immediately. Any idle timeout on the key
+// is kept, and the earlier of the two deadlines still wins. It reports
+// whether the key existed.
+func (c *Cache[K, V]) ExpireAt(key K, t time.Time) bool {
+	c.mu.Lock()
+	defer c.dispatch()
+	defer c.mu.Unlock()
+
+	c.reconcileLocked()
+	return c.expireAtLocked(key, t, c.clock.Now())
+}
+
+// Persist removes any expiration from a key, so that it lives until it is
+// deleted or evicted. It reports whether the key had an expiration to remove.
+func (c *Cache[K, V]) Persist(key K) bool {
+	c.mu.Lock()
+	defer c.dispatch()
+	defer c.mu.Unlock()
+
+	c.reconcileLocked()
+	if c.removeIfExpiredLocked(key, c.clock.Now()) {
+		return false
+	}
+	if _, ok := c.expiries[key]; !ok {
+		return false
+	}
+	c.forgetLocked(key)
+	return true
+}
+
+// Stats returns a snapshot of the statistics recorded since the cache was
+// created. It is all zeroes unless the cache was created WithStats.
+func (c *Cache[K, V]) Stats() cache.Stats {
+	return c.stats.Snapshot()
+}
+
+// DeleteExpired proactively removes every key whose TTL has passed, without
+// waiting for it to be read. It returns the number of keys removed.
+func (c *Cache[K, V]) DeleteExpired() int {
+	c.mu.Lock()
+	defer c.dispatch()
+	defer c.mu.Unlock()
+
+	c.reconcileLocked()
+	return c.deleteExpiredLocked(c.clock.Now())
+}
+
+// Close stops the periodic cleanup started by WithCleanupInterval, and runs
+// the functions registered with OnClose. The cache remains usable
+// afterwards; expired keys are then only removed on access, on writes and by
+// DeleteExpired.
+func (c *Cache[K, V]) Close() {
 	c.mu.Lock()
-	delete(c.expirations, key)
+	c.closed = true
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+	hooks := c.closeHooks
+	c.closeHooks = nil
 	c.mu.Unlock()
 
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+// OnClose registers fn to be called once, when the cache is closed, e.g. to
+// unregister it from a metrics registry. If the cache is already closed, fn
+// is called straight away.
+func (c *Cache[K, V]) OnClose(fn func()) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		fn()
+		return
+	}
+	c.closeHooks = append(c.closeHooks, fn)
+	c.mu.Unlock()
+}
+
+// cleanup is the body of the periodic sweep. It re-arms itself until the
+// cache is closed.
+func (c *Cache[K, V]) cleanup() {
+	c.mu.Lock()
+	defer c.dispatch()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+	c.reconcileLocked()
+	c.deleteExpiredLocked(c.clock.Now())
+	c.janitor.Reset(c.cleanupInterval)
+}
+
+// scheduleLocked records how a key expires, starting from now. A zero expiry
+// means the key never expires. The caller must hold c.mu for writing.
+func (c *Cache[K, V]) scheduleLocked(key K, exp expiry, now time.Time) {
+	if exp == (expiry{}) {
+		c.forgetLocked(key)
+		return
+	}
+	exp.accessed = now
+	c.expiries[key] = exp
+	c.expirations.Schedule(key, exp.deadline(now))
+}
+
+// touchLocked moves the deadline of a key with an idle timeout forward after
+// an access at now. The caller must hold c.mu for writing.
+func (c *Cache[K, V]) touchLocked(key K, now time.Time) {
+	if exp, ok := c.expiries[key]; ok && exp.idle > 0 {
+		exp.accessed = now
+		c.expiries[key] = exp
+		c.expirations.Schedule(key, exp.deadline(now))
+	}
+}
+
+// removeIfExpiredLocked deletes a key from both caches if its deadline has
+// passed, and reports whether it did. The caller must hold c.mu for writing.
+func (c *Cache[K, V]) removeIfExpiredLocked(key K, now time.Time) bool {
+	expiresAt, hasExpiration := c.expirations.Deadline(key)
+	if !hasExpiration || !now.After(expiresAt) {
+		return false
+	}
+	c.forgetLocked(key)
+	c.expireCoreLocked(key)
+	return true
+}
+
+// expireAtLocked gives a key an absolute deadline of t, deleting it if t is
+// not after now. It reports whether the key existed. The caller must hold
+// c.mu for writing.
+func (c *Cache[K, V]) expireAtLocked(key K, t, now time.Time) bool {
+	if c.removeIfExpiredLocked(key, now) {
+		return false
+	}
+
+	exp, hasExpiration := c.expiries[key]
+	if !hasExpiration {
+		// Keys without an expiration are only known to the core cache.
+		if contains, known := c.coreContains(key); known && !contains {
+			return false
+		}
+	}
+
+	if !t.After(now) {
+		c.forgetLocked(key)
+		c.coreCache.Delete(key)
+		return true
+	}
+
+	// Any idle timeout keeps running from the last access rather than
+	// restarting, and the earlier deadline wins.
+	exp.expiresAt = t
+	c.expiries[key] = exp
+	c.expirations.Schedule(key, exp.deadline(exp.accessed))
+	return true
+}
+
+// recordGet records the outcome of a lookup.
+func (c *Cache[K, V]) recordGet(found bool) {
+	if found {
+		c.stats.RecordHits(1)
+	} else {
+		c.stats.RecordMisses(1)
+	}
+}
+
+// recordWriteLocked records a write of key as a set or an update, depending
+// on whether the core cache already holds it. Writes to cores that cannot
+// tell without side effects count as sets. It does nothing, and in
+// particular does not look the key up, when stats are disabled. The caller
+// must hold c.mu for writing.
+func (c *Cache[K, V]) recordWriteLocked(key K) {
+	if c.stats == nil {
+		return
+	}
+	if contains, _ := c.coreContains(key); contains {
+		c.stats.RecordUpdate()
+	} else {
+		c.stats.RecordSet()
+	}
+}
+
+// forgetLocked stops tracking the expiration of a key. The caller must hold
+// c.mu for writing.
+func (c *Cache[K, V]) forgetLocked(key K) {
+	delete(c.expiries, key)
+	c.expirations.Cancel(key)
+}
+
+// deleteExpiredLocked turns the expiration wheel forward to now and removes
+// the keys that came due. The caller must hold c.mu for writing.
+func (c *Cache[K, V]) deleteExpiredLocked(now time.Time) int {
+	removed := 0
+	c.expirations.Advance(now, func(key K) {
+		delete(c.expiries, key)
+		c.expireCoreLocked(key)
+		removed++
+	})
+	return removed
+}
+
+// coreContains reports whether the core cache holds a key, and whether it
+// could tell. Only cores that implement cache.Peeker can: reading any other
+// core with Get would count as an access, in its eviction policy and in
+// whatever it records.
+func (c *Cache[K, V]) coreContains(key K) (contains, known bool) {
+	if core, ok := c.coreCache.(cache.Peeker[K, V]); ok {
+		return core.Contains(key), true
+	}
+	return false, false
+}
+
+// expireCoreLocked deletes an expired key from the core cache, so that the
+// removal is reported as cache.Expired. The caller must hold c.mu for writing.
+func (c *Cache[K, V]) expireCoreLocked(key K) {
+	c.notifyMu.Lock()
+	c.expiring[key] = struct{}{}
+	c.notifyMu.Unlock()
+
 	c.coreCache.Delete(key)
+
+	c.notifyMu.Lock()
+	delete(c.expiring, key)
+	c.notifyMu.Unlock()
+	c.reconcileLocked()
+}
+
+// onCoreRemoval is registered as a listener on the core cache. It only
+// queues the removal; reconcileLocked applies it.
+func (c *Cache[K, V]) onCoreRemoval(key K, value V, reason cache.RemovalReason) {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+
+	if _, ok := c.expiring[key]; ok && reason == cache.Deleted {
+		reason = cache.Expired
+	}
+	c.stats.RecordRemoval(reason)
+	c.pending = append(c.pending, removal[K, V]{key, value, reason})
+}
+
+// reconcileLocked drops the expiration data of keys that the core cache has
+// removed since the last call, e.g. because it evicted them to make room, and
+// queues them for the listeners. The caller must hold c.mu for writing.
+func (c *Cache[K, V]) reconcileLocked() {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+
+	keep := len(c.listeners) > 0 || c.debugEnabled()
+	for _, r := range c.pending {
+		c.forgetLocked(r.key)
+		if keep {
+			c.outbox = append(c.outbox, r)
+		}
+	}
+	clear(c.pending)
+	c.pending = c.pending[:0]
+}
+
+// dispatch passes reconciled removals to the listeners. It must be called
+// without holding c.mu.
+func (c *Cache[K, V]) dispatch() {
+	c.notifyMu.Lock()
+	outbox := c.outbox
+	listeners := c.listeners
+	c.outbox = nil
+	c.notifyMu.Unlock()
+
+	for _, r := range outbox {
+		if r.reason == cache.Expired {
+			c.debug("cache expiration", "key", r.key)
+		}
+		for _, listener := range listeners {
+			listener(r.key, r.value, r.reason)
+		}
+	}
+}
+
+// startSpan starts a span for an operation on key, or returns nil if the
+// cache has no tracer.
+func (c *Cache[K, V]) startSpan(name string, key K) cache.Span {
+	if c.tracer == nil {
+		return nil
+	}
+	return c.tracer.Start(name, slog.Uint64(cache.AttrKeyHash, cache.KeyHash(key)))
+}
+
+// debugEnabled reports whether debug-level events are logged, so that
+// callers can skip building them otherwise.
+func (c *Cache[K, V]) debugEnabled() bool {
+	return c.logger != nil && c.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// debug logs a debug-level event, if debug logging is enabled.
+func (c *Cache[K, V]) debug(msg string, args ...any) {
+	if c.debugEnabled() {
+		c.logger.Debug(msg, args...)
+	}
 }
 
 // Static assertion to ensure *ttl.Cache satisfies the cache.Cacheable interface.
@@ -1,20 +1,26 @@
 package ttl_test
 
 import (
+	"bytes"
+	"log/slog"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/Varun0157/in-mem-cache/cache"
+	"github.com/Varun0157/in-mem-cache/cache/cachetest"
 	"github.com/Varun0157/in-mem-cache/cache/policies"
+	"github.com/Varun0157/in-mem-cache/clock/clocktest"
 	"github.com/Varun0157/in-mem-cache/ttl"
 )
 
 func TestTTLCache_Expiration(t *testing.T) {
 	// 1. Setup: Create a core cache and wrap it with the TTL decorator.
 	core := cache.New[string, string](10, policies.NewLRU[string]())
-	ttlCache := ttl.NewCache(core)
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
 
 	// 2. Act: Set a key with a very short TTL.
 	ttlCache.SetWithTTL("a", "alpha", 50*time.Millisecond)
@@ -24,8 +30,8 @@ func TestTTLCache_Expiration(t *testing.T) {
 	require.True(t, found)
 	require.Equal(t, "alpha", val)
 
-	// 4. Act: Wait for the TTL to expire.
-	time.Sleep(100 * time.Millisecond)
+	// 4. Act: Move the clock past the TTL.
+	clk.Advance(100 * time.Millisecond)
 
 	// 5. Assert: Now the key should be gone.
 	_, found = ttlCache.Get("a")
@@ -34,11 +40,12 @@ func TestTTLCache_Expiration(t *testing.T) {
 
 func TestTTLCache_NoExpiration(t *testing.T) {
 	core := cache.New[string, string](10, policies.NewLRU[string]())
-	ttlCache := ttl.NewCache(core)
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
 
 	// Set a key with no TTL (or using the standard Set method).
 	ttlCache.Set("b", "beta")
-	time.Sleep(50 * time.Millisecond) // Wait a bit
+	clk.Advance(50 * time.Millisecond) // Let some time pass
 
 	// The key should still exist.
 	_, found := ttlCache.Get("b")
@@ -47,7 +54,8 @@ func TestTTLCache_NoExpiration(t *testing.T) {
 
 func TestTTLCache_UpdateTTL(t *testing.T) {
 	core := cache.New[string, string](10, policies.NewLRU[string]())
-	ttlCache := ttl.NewCache(core)
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
 
 	// Set a key with a short TTL
 	ttlCache.SetWithTTL("c", "gamma", 50*time.Millisecond)
@@ -56,7 +64,7 @@ func TestTTLCache_UpdateTTL(t *testing.T) {
 	ttlCache.SetWithTTL("c", "gamma", 200*time.Millisecond)
 
 	// Wait for the original TTL to expire
-	time.Sleep(100 * time.Millisecond)
+	clk.Advance(100 * time.Millisecond)
 
 	// The key should still exist because we updated the TTL
 	val, found := ttlCache.Get("c")
@@ -64,7 +72,7 @@ func TestTTLCache_UpdateTTL(t *testing.T) {
 	require.Equal(t, "gamma", val)
 
 	// Wait for the new TTL to expire
-	time.Sleep(150 * time.Millisecond)
+	clk.Advance(150 * time.Millisecond)
 
 	// Now the key should be gone
 	_, found = ttlCache.Get("c")
@@ -73,7 +81,8 @@ func TestTTLCache_UpdateTTL(t *testing.T) {
 
 func TestTTLCache_Delete(t *testing.T) {
 	core := cache.New[string, string](10, policies.NewLRU[string]())
-	ttlCache := ttl.NewCache(core)
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
 
 	// Set a key with TTL
 	ttlCache.SetWithTTL("d", "delta", 100*time.Millisecond)
@@ -86,9 +95,698 @@ func TestTTLCache_Delete(t *testing.T) {
 	require.False(t, found, "Key 'd' should be deleted")
 
 	// Wait for what would have been the TTL
-	time.Sleep(150 * time.Millisecond)
+	clk.Advance(150 * time.Millisecond)
 
 	// Still should be gone
 	_, found = ttlCache.Get("d")
 	require.False(t, found, "Key 'd' should still be deleted after TTL would have expired")
 }
+
+func TestTTLCache_DeleteExpired(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	ttlCache.SetWithTTL("a", "alpha", 50*time.Millisecond)
+	ttlCache.SetWithTTL("b", "beta", 50*time.Millisecond)
+	ttlCache.SetWithTTL("c", "gamma", time.Hour)
+	ttlCache.Set("d", "delta")
+
+	// Nothing has expired yet
+	require.Equal(t, 0, ttlCache.DeleteExpired())
+
+	clk.Advance(100 * time.Millisecond)
+
+	// Expired keys are removed from the core cache without being read first
+	require.Equal(t, 2, ttlCache.DeleteExpired())
+	_, found := core.Get("a")
+	require.False(t, found)
+	_, found = core.Get("b")
+	require.False(t, found)
+
+	_, found = ttlCache.Get("c")
+	require.True(t, found)
+	_, found = ttlCache.Get("d")
+	require.True(t, found)
+}
+
+func TestTTLCache_ExpiresExactlyAtDeadline(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	ttlCache.SetWithTTL("a", "alpha", time.Minute)
+
+	// Still present right up to the deadline
+	clk.Advance(time.Minute)
+	_, found := ttlCache.Get("a")
+	require.True(t, found)
+
+	clk.Advance(time.Nanosecond)
+	_, found = ttlCache.Get("a")
+	require.False(t, found)
+}
+
+func TestTTLCache_CleanupInterval(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk), ttl.WithCleanupInterval(time.Second))
+
+	ttlCache.SetWithTTL("a", "alpha", 500*time.Millisecond)
+	ttlCache.SetWithTTL("b", "beta", 1500*time.Millisecond)
+
+	// The first sweep removes "a" without it ever being read
+	clk.Advance(time.Second)
+	_, found := core.Get("a")
+	require.False(t, found)
+	_, found = core.Get("b")
+	require.True(t, found)
+
+	// The sweep re-arms itself
+	clk.Advance(time.Second)
+	_, found = core.Get("b")
+	require.False(t, found)
+
+	// Closing stops the sweep
+	ttlCache.Close()
+	require.Equal(t, 0, clk.Waiters())
+	ttlCache.SetWithTTL("c", "gamma", time.Millisecond)
+	clk.Advance(time.Hour)
+	_, found = core.Get("c")
+	require.True(t, found)
+}
+
+func TestTTLCache_IdleTimeout(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	ttlCache.SetWithIdleTimeout("session", "user-1", 30*time.Minute)
+
+	// Each read keeps the session alive for another 30 minutes
+	for range 5 {
+		clk.Advance(20 * time.Minute)
+		_, found := ttlCache.Get("session")
+		require.True(t, found)
+	}
+
+	// Once idle for longer than the timeout, it expires
+	clk.Advance(31 * time.Minute)
+	_, found := ttlCache.Get("session")
+	require.False(t, found, "Session should expire after 30 minutes of inactivity")
+}
+
+func TestTTLCache_IdleTimeoutSweptWhenIdle(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	ttlCache.SetWithIdleTimeout("a", "alpha", time.Minute)
+	ttlCache.SetWithIdleTimeout("b", "beta", time.Minute)
+
+	// Only "b" is read, so only "a" goes idle
+	clk.Advance(45 * time.Second)
+	_, found := ttlCache.Get("b")
+	require.True(t, found)
+	clk.Advance(45 * time.Second)
+
+	require.Equal(t, 1, ttlCache.DeleteExpired())
+	_, found = core.Get("a")
+	require.False(t, found)
+	_, found = core.Get("b")
+	require.True(t, found)
+}
+
+func TestTTLCache_TTLAndIdleTimeout_EarlierDeadlineWins(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	// Idle timeout is the tighter limit while the key is not read
+	ttlCache.SetWithTTLAndIdleTimeout("a", "alpha", time.Hour, time.Minute)
+	clk.Advance(2 * time.Minute)
+	_, found := ttlCache.Get("a")
+	require.False(t, found, "Key 'a' should expire after going idle")
+
+	// Absolute TTL caps the lifetime even if the key keeps being read
+	ttlCache.SetWithTTLAndIdleTimeout("b", "beta", 3*time.Minute, time.Minute)
+	for range 5 {
+		clk.Advance(50 * time.Second)
+		_, found = ttlCache.Get("b")
+		if !found {
+			break
+		}
+	}
+	require.False(t, found, "Key 'b' should expire at its absolute deadline")
+}
+
+func TestTTLCache_TTL(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	ttlCache.SetWithTTL("a", "alpha", time.Minute)
+	ttlCache.Set("b", "beta")
+
+	clk.Advance(20 * time.Second)
+	remaining, ok := ttlCache.TTL("a")
+	require.True(t, ok)
+	require.Equal(t, 40*time.Second, remaining)
+
+	// Keys that never expire, or do not exist, have no TTL
+	_, ok = ttlCache.TTL("b")
+	require.False(t, ok)
+	_, ok = ttlCache.TTL("missing")
+	require.False(t, ok)
+
+	clk.Advance(time.Minute)
+	_, ok = ttlCache.TTL("a")
+	require.False(t, ok)
+}
+
+func TestTTLCache_Expire(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	ttlCache.Set("a", "alpha")
+	ttlCache.SetWithTTL("b", "beta", time.Second)
+
+	// Give a persistent key a TTL, and extend an existing one
+	require.True(t, ttlCache.Expire("a", time.Minute))
+	require.True(t, ttlCache.Expire("b", time.Hour))
+	require.False(t, ttlCache.Expire("missing", time.Minute))
+
+	clk.Advance(30 * time.Minute)
+	_, found := ttlCache.Get("a")
+	require.False(t, found)
+	val, found := ttlCache.Get("b")
+	require.True(t, found)
+	require.Equal(t, "beta", val)
+
+	// A non-positive duration deletes the key right away
+	require.True(t, ttlCache.Expire("b", 0))
+	_, found = core.Get("b")
+	require.False(t, found)
+}
+
+func TestTTLCache_ExpireAt(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	ttlCache.SetWithIdleTimeout("a", "alpha", time.Minute)
+
+	// The idle deadline is earlier, so it still wins
+	require.True(t, ttlCache.ExpireAt("a", clk.Now().Add(time.Hour)))
+	remaining, ok := ttlCache.TTL("a")
+	require.True(t, ok)
+	require.Equal(t, time.Minute, remaining)
+
+	// The absolute deadline is earlier, so it takes over
+	require.True(t, ttlCache.ExpireAt("a", clk.Now().Add(10*time.Second)))
+	remaining, ok = ttlCache.TTL("a")
+	require.True(t, ok)
+	require.Equal(t, 10*time.Second, remaining)
+
+	clk.Advance(11 * time.Second)
+	_, found := ttlCache.Get("a")
+	require.False(t, found)
+}
+
+func TestTTLCache_ExpireWithTTLAndIdleTimeout(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	ttlCache.SetWithTTLAndIdleTimeout("a", "alpha", time.Minute, 10*time.Minute)
+
+	// The new absolute deadline replaces the old one, which was earlier
+	require.True(t, ttlCache.Expire("a", 5*time.Minute))
+	remaining, ok := ttlCache.TTL("a")
+	require.True(t, ok)
+	require.Equal(t, 5*time.Minute, remaining)
+
+	clk.Advance(2 * time.Minute)
+	_, found := ttlCache.Get("a")
+	require.True(t, found)
+
+	// The idle timeout runs from the last access, not from the Expire call
+	require.True(t, ttlCache.Expire("a", time.Hour))
+	remaining, ok = ttlCache.TTL("a")
+	require.True(t, ok)
+	require.Equal(t, 10*time.Minute, remaining)
+
+	clk.Advance(10*time.Minute + time.Second)
+	_, found = ttlCache.Get("a")
+	require.False(t, found)
+}
+
+func TestTTLCache_Persist(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	ttlCache.SetWithTTL("a", "alpha", time.Minute)
+	ttlCache.Set("b", "beta")
+
+	require.True(t, ttlCache.Persist("a"))
+	require.False(t, ttlCache.Persist("b"), "Key 'b' had no expiration to remove")
+	require.False(t, ttlCache.Persist("missing"))
+
+	clk.Advance(time.Hour)
+	val, found := ttlCache.Get("a")
+	require.True(t, found)
+	require.Equal(t, "alpha", val)
+
+	// An already-expired key cannot be rescued
+	ttlCache.SetWithTTL("c", "gamma", time.Second)
+	clk.Advance(2 * time.Second)
+	require.False(t, ttlCache.Persist("c"))
+	_, found = ttlCache.Get("c")
+	require.False(t, found)
+}
+
+func TestTTLCache_ConcurrentExpireAndGet(t *testing.T) {
+	core := cache.New[int, int](100, policies.NewLRU[int]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+	var wg sync.WaitGroup
+
+	for i := range 100 {
+		ttlCache.SetWithTTL(i, i, time.Second)
+	}
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			ttlCache.Persist(i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			ttlCache.Get(i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		clk.Advance(2 * time.Second)
+	}()
+	wg.Wait()
+
+	// Every key either became persistent before it expired, or is gone
+	for i := range 100 {
+		_, hasTTL := ttlCache.TTL(i)
+		require.False(t, hasTTL)
+		val, found := ttlCache.Get(i)
+		_, inCore := core.Get(i)
+		require.Equal(t, inCore, found)
+		if found {
+			require.Equal(t, i, val)
+		}
+	}
+}
+
+func TestTTLCache_CoreEvictionDropsExpiration(t *testing.T) {
+	core := cache.New[int, int](10, policies.NewFIFO[int]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	// Fill the core cache well past its capacity
+	for i := range 100 {
+		ttlCache.SetWithTTL(i, i, time.Hour)
+	}
+
+	// Only the keys still in the core cache are tracked
+	require.Equal(t, 10, ttlCache.TrackedLen())
+	require.Equal(t, 10, ttlCache.ScheduledLen())
+
+	// Re-adding an evicted key without a TTL must not pick up its old deadline
+	ttlCache.Set(0, 0)
+	clk.Advance(2 * time.Hour)
+	val, found := ttlCache.Get(0)
+	require.True(t, found)
+	require.Equal(t, 0, val)
+}
+
+func TestTTLCache_DirectCoreRemoval(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	ttlCache.SetWithTTL("a", "alpha", time.Minute)
+
+	// Deleting through the core cache is picked up by the next operation
+	core.Delete("a")
+	ttlCache.Set("b", "beta")
+	require.Equal(t, 0, ttlCache.TrackedLen())
+	require.Equal(t, 0, ttlCache.ScheduledLen())
+}
+
+func TestTTLCache_RemovalListener(t *testing.T) {
+	core := cache.New[string, string](2, policies.NewFIFO[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	reasons := make(map[string]cache.RemovalReason)
+	ttlCache.AddRemovalListener(func(key string, _ string, reason cache.RemovalReason) {
+		reasons[key] = reason
+	})
+
+	ttlCache.SetWithTTL("a", "alpha", time.Minute)
+	ttlCache.Set("b", "beta")
+	ttlCache.Set("c", "gamma") // Evicts "a"
+	ttlCache.Delete("b")
+	ttlCache.SetWithTTL("d", "delta", time.Minute)
+
+	clk.Advance(2 * time.Minute)
+	_, found := ttlCache.Get("d")
+	require.False(t, found)
+
+	require.Equal(t, map[string]cache.RemovalReason{
+		"a": cache.Evicted,
+		"b": cache.Deleted,
+		"d": cache.Expired,
+	}, reasons)
+}
+
+func TestTTLCache_CoreClear(t *testing.T) {
+	core := cache.New[int, int](10, policies.NewLRU[int]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	for i := range 5 {
+		ttlCache.SetWithTTL(i, i, time.Minute)
+	}
+
+	// Clearing the core cache drops every expiration with it
+	core.Clear()
+	ttlCache.DeleteExpired()
+	require.Equal(t, 0, ttlCache.TrackedLen())
+	require.Equal(t, 0, ttlCache.ScheduledLen())
+}
+
+func TestTTLCache_GetMany(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	ttlCache.SetWithTTL("a", "alpha", time.Minute)
+	ttlCache.SetWithIdleTimeout("b", "beta", time.Minute)
+	ttlCache.Set("c", "gamma")
+
+	require.Equal(t, map[string]string{"a": "alpha", "b": "beta", "c": "gamma"},
+		ttlCache.GetMany([]string{"a", "b", "c", "missing"}))
+
+	// Both "a" and "b" have run out of time
+	clk.Advance(90 * time.Second)
+	require.Equal(t, map[string]string{"c": "gamma"}, ttlCache.GetMany([]string{"a", "b", "c"}))
+
+	// Reading "b" in a batch keeps it alive
+	ttlCache.SetWithIdleTimeout("b", "beta", time.Minute)
+	clk.Advance(45 * time.Second)
+	ttlCache.GetMany([]string{"b"})
+	clk.Advance(45 * time.Second)
+	require.Equal(t, map[string]string{"b": "beta"}, ttlCache.GetMany([]string{"b"}))
+}
+
+func TestTTLCache_SetManyWithTTL(t *testing.T) {
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	ttlCache.SetManyWithTTL([]ttl.Item[string, string]{
+		{Key: "a", Value: "alpha", TTL: time.Minute},
+		{Key: "b", Value: "beta", TTL: time.Hour},
+		{Key: "c", Value: "gamma"},
+		{Key: "d", Value: "delta", Idle: time.Minute},
+	})
+	ttlCache.SetMany(map[string]string{"e": "epsilon"})
+
+	clk.Advance(2 * time.Minute)
+	require.Equal(t, map[string]string{"b": "beta", "c": "gamma", "e": "epsilon"},
+		ttlCache.GetMany([]string{"a", "b", "c", "d", "e"}))
+}
+
+func TestTTLCache_SetManyEvictsOwnItems(t *testing.T) {
+	core := cache.New[int, int](5, policies.NewFIFO[int]())
+	ttlCache := ttl.NewCache(core)
+
+	items := make([]ttl.Item[int, int], 20)
+	for i := range items {
+		items[i] = ttl.Item[int, int]{Key: i, Value: i, TTL: time.Hour}
+	}
+	ttlCache.SetManyWithTTL(items)
+
+	// Only the items that survived in the core cache keep an expiration
+	require.Equal(t, 5, core.Len())
+	require.Equal(t, 5, ttlCache.TrackedLen())
+	require.Equal(t, 5, ttlCache.ScheduledLen())
+}
+
+func TestTTLCache_Compute(t *testing.T) {
+	core := cache.New[string, int](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	ttlCache.SetWithTTL("a", 1, time.Minute)
+
+	// Updating keeps the existing expiration
+	val, ok := ttlCache.Update("a", func(old int) int { return old + 1 })
+	require.True(t, ok)
+	require.Equal(t, 2, val)
+	remaining, ok := ttlCache.TTL("a")
+	require.True(t, ok)
+	require.Equal(t, time.Minute, remaining)
+
+	// An expired entry counts as absent
+	clk.Advance(2 * time.Minute)
+	actual, loaded := ttlCache.SetIfAbsent("a", 10)
+	require.False(t, loaded)
+	require.Equal(t, 10, actual)
+
+	// The new entry does not inherit the old expiration
+	_, ok = ttlCache.TTL("a")
+	require.False(t, ok)
+
+	require.True(t, ttlCache.CompareAndSwap("a", 10, 11, nil))
+	_, ok = ttlCache.Compute("a", func(int, bool) (int, cache.Op) { return 0, cache.OpDelete })
+	require.False(t, ok)
+	require.False(t, core.Contains("a"))
+}
+
+func TestTTLCache_ConcurrentUpdate(t *testing.T) {
+	core := cache.New[string, int](10, policies.NewLRU[string]())
+	ttlCache := ttl.NewCache(core)
+	ttlCache.SetWithTTL("counter", 0, time.Hour)
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				ttlCache.Update("counter", func(old int) int { return old + 1 })
+			}
+		}()
+	}
+	wg.Wait()
+
+	val, _ := ttlCache.Get("counter")
+	require.Equal(t, 10000, val)
+}
+
+func TestTTLCache_ComputePanicReleasesLock(t *testing.T) {
+	core := cache.New[string, []int](10, policies.NewLRU[string]())
+	ttlCache := ttl.NewCache(core)
+	ttlCache.Set("a", []int{1})
+
+	// A nil equal cannot compare slices
+	require.Panics(t, func() { ttlCache.CompareAndSwap("a", []int{1}, []int{2}, nil) })
+
+	val, found := ttlCache.Get("a")
+	require.True(t, found)
+	require.Equal(t, []int{1}, val)
+}
+
+func TestTTLCache_ComputeWithTTL(t *testing.T) {
+	core := cache.New[string, int](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Now())
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk))
+
+	incr := func(old int, _ bool) (int, cache.Op) { return old + 1, cache.OpStore }
+
+	// The TTL applies to the entry created by the first call only
+	ttlCache.ComputeWithTTL("a", time.Minute, incr)
+	clk.Advance(30 * time.Second)
+	ttlCache.ComputeWithTTL("a", time.Minute, incr)
+
+	remaining, ok := ttlCache.TTL("a")
+	require.True(t, ok)
+	require.Equal(t, 30*time.Second, remaining)
+
+	// Existing entries without a TTL do not pick one up
+	ttlCache.Set("b", 0)
+	ttlCache.ComputeWithTTL("b", time.Minute, incr)
+	_, ok = ttlCache.TTL("b")
+	require.False(t, ok)
+}
+
+func TestTTLCache_Stats(t *testing.T) {
+	core := cache.New[string, string](2, policies.NewFIFO[string]())
+	clk := clocktest.NewFake(time.Unix(0, 0))
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk), ttl.WithStats())
+
+	ttlCache.SetWithTTL("a", "alpha", time.Second)
+	ttlCache.Set("a", "alpha") // An update
+	ttlCache.SetWithTTL("b", "beta", time.Second)
+
+	ttlCache.Get("a")
+	clk.Advance(2 * time.Second)
+
+	// Expired entries are misses, and their removal is an expiration
+	ttlCache.Get("b")
+	ttlCache.GetMany([]string{"a", "c"})
+
+	ttlCache.Set("c", "gamma")
+	ttlCache.Set("d", "delta")   // Evicts "a" from the core
+	ttlCache.Set("e", "epsilon") // Evicts "c"
+	ttlCache.Delete("e")
+
+	require.Equal(t, cache.Stats{
+		Hits:        2,
+		Misses:      2,
+		Sets:        5,
+		Updates:     1,
+		Deletes:     1,
+		Evictions:   2,
+		Expirations: 1,
+	}, ttlCache.Stats())
+}
+
+// opaqueCore hides every method of a core cache beyond Cacheable, and counts
+// its reads.
+type opaqueCore struct {
+	cache.Cacheable[string, string]
+	gets int
+}
+
+func (c *opaqueCore) Get(key string) (string, bool) {
+	c.gets++
+	return c.Cacheable.Get(key)
+}
+
+func TestTTLCache_StatsGetManyDuplicates(t *testing.T) {
+	core := cache.New[string, string](10, nil)
+	ttlCache := ttl.NewCache(core, ttl.WithStats())
+	ttlCache.Set("a", "alpha")
+
+	// Each key asked for counts, as with Get
+	ttlCache.GetMany([]string{"a", "a", "a", "x", "x"})
+	stats := ttlCache.Stats()
+	require.Equal(t, uint64(3), stats.Hits)
+	require.Equal(t, uint64(2), stats.Misses)
+}
+
+func TestTTLCache_StatsDoNotReadCore(t *testing.T) {
+	core := &opaqueCore{Cacheable: cache.New[string, string](10, nil)}
+	ttlCache := ttl.NewCache[string, string](core, ttl.WithStats())
+
+	// Without Contains, the core cannot be asked, so both writes are sets
+	ttlCache.Set("a", "alpha")
+	ttlCache.Set("a", "alpha")
+	require.True(t, ttlCache.Expire("a", time.Minute))
+	require.Zero(t, core.gets)
+
+	stats := ttlCache.Stats()
+	require.Equal(t, uint64(2), stats.Sets)
+	require.Zero(t, stats.Updates)
+
+	// A sharded core can be asked
+	sharded := cache.NewSharded[string, string](4, 10, nil, nil)
+	ttlCache = ttl.NewCache[string, string](sharded, ttl.WithStats())
+	ttlCache.Set("a", "alpha")
+	ttlCache.Set("a", "alpha")
+	require.False(t, ttlCache.Expire("b", time.Minute))
+
+	stats = ttlCache.Stats()
+	require.Equal(t, uint64(1), stats.Sets)
+	require.Equal(t, uint64(1), stats.Updates)
+}
+
+func TestTTLCache_GetOrLoadWithTTL(t *testing.T) {
+	core := cache.New[string, int](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Unix(0, 0))
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk), ttl.WithStats())
+
+	calls := 0
+	loader := func(key string) (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	value, err := ttlCache.GetOrLoadWithTTL("a", time.Second, loader)
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	value, err = ttlCache.GetOrLoadWithTTL("a", time.Second, loader)
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	// Once the loaded value expires, it is loaded again
+	clk.Advance(2 * time.Second)
+	value, err = ttlCache.GetOrLoadWithTTL("a", time.Second, loader)
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+
+	stats := ttlCache.Stats()
+	require.Equal(t, uint64(2), stats.LoadSuccesses)
+	require.Equal(t, uint64(1), stats.Hits)
+	require.Equal(t, uint64(1), stats.Expirations)
+}
+
+func TestTTLCache_LogsExpirations(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Unix(0, 0))
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk), ttl.WithLogger(logger.With("cache", "sessions")))
+
+	ttlCache.SetWithTTL("a", "alpha", time.Second)
+	ttlCache.Delete("missing")
+	require.Empty(t, buf.String())
+
+	clk.Advance(2 * time.Second)
+	ttlCache.DeleteExpired()
+	require.Contains(t, buf.String(), `level=DEBUG msg="cache expiration" cache=sessions key=a`)
+}
+
+func TestTTLCache_Tracer(t *testing.T) {
+	tracer := cachetest.NewTracer()
+	core := cache.New[string, string](10, policies.NewLRU[string]())
+	clk := clocktest.NewFake(time.Unix(0, 0))
+	ttlCache := ttl.NewCache(core, ttl.WithClock(clk), ttl.WithTracer(tracer))
+
+	ttlCache.SetWithTTL("a", "alpha", time.Second)
+	ttlCache.Get("a")
+	clk.Advance(2 * time.Second)
+	ttlCache.Get("a")
+	ttlCache.Delete("a")
+
+	spans := tracer.Spans()
+	require.Len(t, spans, 4)
+	require.Equal(t, cache.SpanSet, spans[0].Name)
+	require.Equal(t, cache.SpanDelete, spans[3].Name)
+	for _, span := range spans {
+		require.True(t, span.Ended, span.Name)
+		require.Equal(t, cache.KeyHash("a"), span.Attrs[cache.AttrKeyHash].Uint64())
+	}
+
+	// A hit, then a miss because the entry had expired
+	require.True(t, spans[1].Attrs[cache.AttrHit].Bool())
+	require.False(t, spans[1].Attrs[cache.AttrExpired].Bool())
+	require.False(t, spans[2].Attrs[cache.AttrHit].Bool())
+	require.True(t, spans[2].Attrs[cache.AttrExpired].Bool())
+}
@@ -0,0 +1,46 @@
+package workload
+
+import "math/rand/v2"
+
+// Hotspot draws most keys from a small hot set that moves every so often,
+// and the rest uniformly from all n keys. It models popularity that drifts,
+// such as trending items, which a policy must adapt to rather than learn
+// once.
+type Hotspot struct {
+	n, hot     uint64
+	fraction   float64
+	shiftEvery uint64
+	offset     uint64 // First key of the hot set
+	count      uint64 // Keys returned since the last shift
+	rng        *rand.Rand
+}
+
+// NewHotspot creates a Hotspot generator over n keys, of which hot are hot
+// at any time and receive the given fraction of the requests on top of
+// their uniform share. Every shiftEvery keys, the hot set moves on to the
+// next hot keys, wrapping around at n. A non-positive shiftEvery never moves
+// it. Out-of-range arguments are clamped.
+func NewHotspot(n, hot int, fraction float64, shiftEvery int, seed uint64) *Hotspot {
+	n = max(n, 1)
+	return &Hotspot{
+		n:          uint64(n),
+		hot:        uint64(min(max(hot, 1), n)),
+		fraction:   min(max(fraction, 0), 1),
+		shiftEvery: uint64(max(shiftEvery, 0)),
+		rng:        newRand(seed),
+	}
+}
+
+// Next returns the next key.
+func (h *Hotspot) Next() uint64 {
+	if h.shiftEvery > 0 && h.count == h.shiftEvery {
+		h.offset = (h.offset + h.hot) % h.n
+		h.count = 0
+	}
+	h.count++
+
+	if h.rng.Float64() < h.fraction {
+		return (h.offset + h.rng.Uint64N(h.hot)) % h.n
+	}
+	return h.rng.Uint64N(h.n)
+}
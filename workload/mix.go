@@ -0,0 +1,40 @@
+package workload
+
+import "math/rand/v2"
+
+// Op is a single cache operation.
+type Op struct {
+	Key   uint64
+	Write bool // Whether the operation stores Key rather than reading it
+}
+
+// Mix turns a key stream into a stream of reads and writes.
+type Mix struct {
+	keys       Generator
+	writeRatio float64
+	rng        *rand.Rand
+}
+
+// NewMix creates a Mix that draws keys from keys and makes the given
+// fraction of operations writes. The ratio is clamped to [0, 1].
+func NewMix(keys Generator, writeRatio float64, seed uint64) *Mix {
+	return &Mix{
+		keys:       keys,
+		writeRatio: min(max(writeRatio, 0), 1),
+		rng:        newRand(seed),
+	}
+}
+
+// Next returns the next operation.
+func (m *Mix) Next() Op {
+	return Op{Key: m.keys.Next(), Write: m.rng.Float64() < m.writeRatio}
+}
+
+// Ops returns the next n operations of m.
+func Ops(m *Mix, n int) []Op {
+	ops := make([]Op, n)
+	for i := range ops {
+		ops[i] = m.Next()
+	}
+	return ops
+}
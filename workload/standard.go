@@ -0,0 +1,21 @@
+package workload
+
+// Workload is a named key stream, for running the same benchmark against
+// several distributions.
+type Workload struct {
+	Name string
+	New  func(seed uint64) Generator
+}
+
+// Standard returns the workloads used by this module's benchmarks, over a
+// key space of n keys: "zipf", "uniform", "scan", "loop" and "hotspot".
+// Benchmarks should use a cache smaller than n, so that the policy matters.
+func Standard(n int) []Workload {
+	return []Workload{
+		{"zipf", func(seed uint64) Generator { return NewZipf(n, DefaultSkew, seed) }},
+		{"uniform", func(seed uint64) Generator { return NewUniform(n, seed) }},
+		{"scan", func(seed uint64) Generator { return NewScan(seed << 32) }}, // Disjoint per seed
+		{"loop", func(seed uint64) Generator { return NewLoop(n) }},
+		{"hotspot", func(seed uint64) Generator { return NewHotspot(n, n/100, 0.9, n, seed) }},
+	}
+}
@@ -0,0 +1,89 @@
+// Package workload generates synthetic key streams for benchmarking caches.
+//
+// Each Generator produces uint64 keys following a distribution that real
+// caches meet: skewed popularity (Zipf), no locality at all (Uniform),
+// one-off sequential reads (Scan), working sets slightly too large for the
+// cache (Loop) and popularity that drifts over time (Hotspot). A Mix turns a
+// key stream into a stream of reads and writes.
+//
+// Generators are deterministic for a given seed, so runs can be compared,
+// and are not safe for concurrent use: give each goroutine its own.
+package workload
+
+import "math/rand/v2"
+
+// Generator produces a stream of keys.
+type Generator interface {
+	// Next returns the next key in the stream.
+	Next() uint64
+}
+
+// Keys returns the next n keys of g. Benchmarks can generate their keys up
+// front, so that the generator's cost is not part of the measurement.
+func Keys(g Generator, n int) []uint64 {
+	keys := make([]uint64, n)
+	for i := range keys {
+		keys[i] = g.Next()
+	}
+	return keys
+}
+
+// Uniform draws keys in [0, n) with equal probability.
+type Uniform struct {
+	n   uint64
+	rng *rand.Rand
+}
+
+// NewUniform creates a Uniform generator over n keys. A non-positive n
+// means a single key.
+func NewUniform(n int, seed uint64) *Uniform {
+	return &Uniform{n: uint64(max(n, 1)), rng: newRand(seed)}
+}
+
+// Next returns the next key.
+func (u *Uniform) Next() uint64 {
+	return u.rng.Uint64N(u.n)
+}
+
+// Scan returns consecutive keys, starting at start, and never repeats one.
+// No cache can hit on it, so it measures the cost of a miss and an eviction,
+// and how much a burst of one-off keys disturbs a policy.
+type Scan struct {
+	next uint64
+}
+
+// NewScan creates a Scan starting at key start.
+func NewScan(start uint64) *Scan {
+	return &Scan{next: start}
+}
+
+// Next returns the next key.
+func (s *Scan) Next() uint64 {
+	key := s.next
+	s.next++
+	return key
+}
+
+// Loop cycles through the keys 0 to n-1 in order. Once n exceeds a cache's
+// capacity, LRU and FIFO miss on every access, while a policy that keeps old
+// entries, such as LIFO, still hits.
+type Loop struct {
+	n, next uint64
+}
+
+// NewLoop creates a Loop over n keys. A non-positive n means a single key.
+func NewLoop(n int) *Loop {
+	return &Loop{n: uint64(max(n, 1))}
+}
+
+// Next returns the next key.
+func (l *Loop) Next() uint64 {
+	key := l.next
+	l.next = (l.next + 1) % l.n
+	return key
+}
+
+// newRand returns a PCG source seeded from seed.
+func newRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+}
@@ -0,0 +1,101 @@
+package workload_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Varun0157/in-mem-cache/workload"
+)
+
+// counts draws n keys from g and counts each one.
+func counts(g workload.Generator, n int) map[uint64]int {
+	seen := make(map[uint64]int)
+	for range n {
+		seen[g.Next()]++
+	}
+	return seen
+}
+
+func TestSeedsAreDeterministic(t *testing.T) {
+	for _, w := range workload.Standard(1000) {
+		t.Run(w.Name, func(t *testing.T) {
+			first := workload.Keys(w.New(7), 500)
+			require.Equal(t, first, workload.Keys(w.New(7), 500))
+		})
+	}
+
+	require.NotEqual(t,
+		workload.Keys(workload.NewUniform(1000, 1), 100),
+		workload.Keys(workload.NewUniform(1000, 2), 100))
+}
+
+func TestUniform(t *testing.T) {
+	seen := counts(workload.NewUniform(10, 1), 10_000)
+	require.Len(t, seen, 10)
+	for key, count := range seen {
+		require.Less(t, key, uint64(10))
+		require.InDelta(t, 1000, count, 150)
+	}
+}
+
+func TestZipf(t *testing.T) {
+	for _, skew := range []float64{0.99, 1.2} {
+		seen := counts(workload.NewZipf(1000, skew, 1), 100_000)
+		for key := range seen {
+			require.Less(t, key, uint64(1000))
+		}
+		// The most popular keys take a large share, in rank order.
+		require.Greater(t, seen[0], 10_000, "skew %v", skew)
+		require.Greater(t, seen[0], seen[1], "skew %v", skew)
+		require.Greater(t, seen[1], seen[10], "skew %v", skew)
+		require.Greater(t, seen[10], seen[500], "skew %v", skew)
+	}
+
+	// Invalid skews fall back to the default.
+	require.Equal(t,
+		workload.Keys(workload.NewZipf(100, workload.DefaultSkew, 3), 100),
+		workload.Keys(workload.NewZipf(100, 1, 3), 100))
+}
+
+func TestScanAndLoop(t *testing.T) {
+	require.Equal(t, []uint64{5, 6, 7, 8}, workload.Keys(workload.NewScan(5), 4))
+	require.Equal(t, []uint64{0, 1, 2, 0, 1, 2, 0}, workload.Keys(workload.NewLoop(3), 7))
+	require.Equal(t, []uint64{0, 0}, workload.Keys(workload.NewLoop(0), 2))
+}
+
+func TestHotspot(t *testing.T) {
+	// 90% of requests go to the 10 hot keys, which move every 1000.
+	g := workload.NewHotspot(1000, 10, 0.9, 1000, 1)
+
+	first := counts(g, 1000)
+	hot := 0
+	for key := range 10 {
+		hot += first[uint64(key)]
+	}
+	require.Greater(t, hot, 850)
+
+	second := counts(g, 1000)
+	hot = 0
+	for key := 10; key < 20; key++ {
+		hot += second[uint64(key)]
+	}
+	require.Greater(t, hot, 850)
+	require.Less(t, second[0], 10)
+}
+
+func TestMix(t *testing.T) {
+	ops := workload.Ops(workload.NewMix(workload.NewLoop(4), 0.25, 1), 10_000)
+	writes := 0
+	for i, op := range ops {
+		require.Equal(t, uint64(i%4), op.Key)
+		if op.Write {
+			writes++
+		}
+	}
+	require.InDelta(t, 2500, writes, 250)
+
+	for _, op := range workload.Ops(workload.NewMix(workload.NewLoop(4), 2, 1), 100) {
+		require.True(t, op.Write)
+	}
+}
@@ -0,0 +1,73 @@
+package workload
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// DefaultSkew is the Zipf exponent used when NewZipf is given an invalid
+// one. It is the YCSB default, under which a few percent of the keys
+// receive most of the requests.
+const DefaultSkew = 0.99
+
+// Zipf draws keys in [0, n) with probability proportional to 1/(k+1)^skew,
+// so key 0 is the most popular. This is the popularity distribution of most
+// real cache traffic.
+type Zipf struct {
+	n   uint64
+	rng *rand.Rand
+
+	// For skews above 1, the standard library's rejection sampler.
+	zipf *rand.Zipf
+
+	// For skews below 1, the method of Gray et al., "Quickly Generating
+	// Billion-Record Synthetic Databases", as used by YCSB.
+	skew, zetan, alpha, eta, half float64
+}
+
+// NewZipf creates a Zipf generator over n keys. The skew must be positive
+// and not 1; other values mean DefaultSkew. A non-positive n means a single
+// key. Skews below 1 take O(n) time to set up.
+func NewZipf(n int, skew float64, seed uint64) *Zipf {
+	if !(skew > 0) || skew == 1 || math.IsInf(skew, 0) {
+		skew = DefaultSkew
+	}
+	z := &Zipf{n: uint64(max(n, 1)), rng: newRand(seed), skew: skew}
+	if skew > 1 {
+		z.zipf = rand.NewZipf(z.rng, skew, 1, z.n-1)
+		return z
+	}
+
+	z.zetan = zeta(z.n, skew)
+	z.alpha = 1 / (1 - skew)
+	z.eta = (1 - math.Pow(2/float64(z.n), 1-skew)) / (1 - zeta(2, skew)/z.zetan)
+	z.half = 1 + math.Pow(0.5, skew)
+	return z
+}
+
+// Next returns the next key.
+func (z *Zipf) Next() uint64 {
+	if z.zipf != nil {
+		return z.zipf.Uint64()
+	}
+
+	u := z.rng.Float64()
+	uz := u * z.zetan
+	switch {
+	case uz < 1:
+		return 0
+	case uz < z.half:
+		return min(1, z.n-1)
+	}
+	key := uint64(float64(z.n) * math.Pow(z.eta*u-z.eta+1, z.alpha))
+	return min(key, z.n-1)
+}
+
+// zeta returns the generalized harmonic number of order s of n.
+func zeta(n uint64, s float64) float64 {
+	sum := 0.0
+	for i := uint64(1); i <= n; i++ {
+		sum += 1 / math.Pow(float64(i), s)
+	}
+	return sum
+}